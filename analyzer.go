@@ -15,9 +15,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -26,19 +28,20 @@ type Analyzer struct {
 	config        *Config
 	logger        *Logger
 	weatherClient *WeatherClient
+	periodWeather map[string]map[string]*WeatherData // Weather already fetched, by period
 }
 
 // NewAnalyzer creates a new analyzer
-func NewAnalyzer(config *Config, logger *Logger) *Analyzer {
+func NewAnalyzer(config *Config, weatherClient *WeatherClient, logger *Logger) *Analyzer {
 	return &Analyzer{
 		config:        config,
 		logger:        logger,
-		weatherClient: NewWeatherClient(logger),
+		weatherClient: weatherClient,
 	}
 }
 
 // Analyze performs complete analysis on collected data
-func (a *Analyzer) Analyze(data *CollectedData) (*AnalysisResult, error) {
+func (a *Analyzer) Analyze(ctx context.Context, data *CollectedData) (*AnalysisResult, error) {
 	a.logger.Info("Starting analysis")
 
 	if data.Account == nil {
@@ -71,37 +74,98 @@ func (a *Analyzer) Analyze(data *CollectedData) (*AnalysisResult, error) {
 	}
 
 	result := &AnalysisResult{
-		GeneratedAt:                 time.Now(),
+		GeneratedAt:                 now(),
 		CurrentBalance:              data.Account.Balance,
 		CurrentDirectDebit:          a.config.DirectDebitAmount,
 		ElectricityAgreements:       data.ElectricityAgreements,
 		ElectricityExportAgreements: data.ElectricityExportAgreements,
 		GasAgreements:               data.GasAgreements,
+		Region:                      data.Region,
+		RegionSource:                data.RegionSource,
+		PricingModel:                data.PricingModel,
+		Statements:                  data.Statements,
+		Payments:                    data.Payments,
+		MeterSegments:               data.MeterSegments,
+		MeterExchanges:              data.MeterExchanges,
+		PropertyID:                  data.PropertyID,
+		PropertyAddress:             data.PropertyAddress,
 	}
 
+	// Smart-charge dispatches repriced at off-peak during collection
+	result.DispatchCount = len(data.Dispatches)
+	result.DispatchKWh = data.DispatchKWh
+	result.DispatchSavings = data.DispatchSavings / 100
+
+	// Prefer what Octopus actually collects over the configured amount
+	a.resolveDirectDebit(result, data.PaymentSchedule)
+
 	// Calculate analysis period
 	if len(data.ElectricityConsumption) > 0 || len(data.GasConsumption) > 0 {
 		result.AnalysisPeriodDays = a.config.AnalysisPeriodDays
-		result.AnalysisPeriodEnd = time.Now()
+		result.AnalysisPeriodEnd = now()
 		result.AnalysisPeriodStart = result.AnalysisPeriodEnd.AddDate(0, 0, -a.config.AnalysisPeriodDays)
 	}
 
+	// Analyse each property on its own alongside the account-level rollup. Their
+	// averages are each based on their own coverage, so they're needed first.
+	for _, property := range data.Properties {
+		result.Properties = append(result.Properties, a.analyzeProperty(ctx, property))
+	}
+
+	// Measure how much of the period the meters actually cover
+	a.logger.LogAnalysisStage("data_coverage")
+	if len(result.Properties) > 0 {
+		result.DataCoverage = propertyCoverage(result.Properties)
+	} else {
+		result.DataCoverage = a.assessCoverage(data, result.AnalysisPeriodStart, result.AnalysisPeriodEnd)
+	}
+	result.DataConfidence = overallConfidence(result.DataCoverage)
+
+	// Fetch the period's weather once for heating analysis, the weather
+	// anomaly model and anomaly context
+	var weather map[string]*WeatherData
+	if !result.AnalysisPeriodStart.IsZero() {
+		a.logger.LogAnalysisStage("period_weather")
+		weather = a.fetchPeriodWeather(ctx, result.AnalysisPeriodStart, result.AnalysisPeriodEnd)
+	}
+
+	// Fit usage against heating degree-days
+	a.logger.LogAnalysisStage("heating_analysis")
+	a.analyzeHeating(result, data, weather)
+
+	// Score usage against the configured anomaly models. A rollup's readings
+	// pool several homes, so its half-hours are only scored per property.
+	detector := NewAnomalyDetector(a.config, weather, a.logger)
+	if len(data.Properties) > 0 {
+		detector.Disable(AnomalyModelHalfHourly)
+	}
+
 	// Analyze electricity consumption
 	if len(data.ElectricityConsumption) > 0 {
 		a.logger.LogAnalysisStage("electricity_consumption")
-		result.AvgDailyElectricity = a.calculateAverageConsumption(data.ElectricityConsumption)
-		result.AvgDailyCostElectricity = a.calculateAverageCost(data.ElectricityConsumption)
+		readings, days := a.coveredReadings(data, data.ElectricityConsumption, result.DataCoverage, "electricity")
+		result.AvgDailyElectricity = a.calculateAverageConsumption(readings, days)
+		result.AvgDailyCostElectricity = a.calculateAverageCost(readings, days)
 
 		// Detect electricity anomalies
-		anomalies := a.detectAnomalies(data.ElectricityConsumption, "electricity")
+		anomalies := detector.Detect(data.ElectricityConsumption, "electricity")
 		result.Anomalies = append(result.Anomalies, anomalies...)
+
+		// Estimate always-on load from the quietest half-hours
+		if len(data.Properties) == 0 {
+			a.analyzeBaseload(result, data)
+		}
+
+		// Work out when electricity is used and what moving it would save
+		a.analyzeLoadProfile(result, data)
 	}
 
 	// Analyze electricity exports (solar/battery)
 	if len(data.ElectricityExport) > 0 {
 		a.logger.LogAnalysisStage("electricity_export")
-		result.AvgDailyExport = a.calculateAverageConsumption(data.ElectricityExport)
-		result.AvgDailyEarningsExport = a.calculateAverageCost(data.ElectricityExport)
+		readings, days := a.coveredReadings(data, data.ElectricityExport, result.DataCoverage, "export")
+		result.AvgDailyExport = a.calculateAverageConsumption(readings, days)
+		result.AvgDailyEarningsExport = a.calculateAverageCost(readings, days)
 		a.logger.Info("Export analysis",
 			"avg_daily_export_kwh", result.AvgDailyExport,
 			"avg_daily_earnings", result.AvgDailyEarningsExport,
@@ -111,23 +175,54 @@ func (a *Analyzer) Analyze(data *CollectedData) (*AnalysisResult, error) {
 	// Analyze gas consumption
 	if len(data.GasConsumption) > 0 {
 		a.logger.LogAnalysisStage("gas_consumption")
-		result.AvgDailyGas = a.calculateAverageConsumption(data.GasConsumption)
-		result.AvgDailyCostGas = a.calculateAverageCost(data.GasConsumption)
+		readings, days := a.coveredReadings(data, data.GasConsumption, result.DataCoverage, "gas")
+		result.AvgDailyGas = a.calculateAverageConsumption(readings, days)
+		result.AvgDailyCostGas = a.calculateAverageCost(readings, days)
+
+		// Keep the metered volume alongside kWh for meters that report m³
+		result.GasUnit = data.GasUnit
+		result.GasUnitSource = data.GasUnitSource
+		if data.GasUnit == GasUnitCubicMetres || data.GasUnit == GasUnitMixed {
+			volume, calorificValue := summarizeGasVolume(readings)
+			volumeDays := days
+			if data.GasUnit == GasUnitMixed {
+				// Only the serials reporting m³ have a volume to average
+				volumeDays = meteredVolumeDays(data.MeterSegments)
+			}
+			result.AvgDailyGasVolume = averageOverCoverage(volume, volumeDays, a.config.AnalysisPeriodDays)
+			result.GasCalorificValue = calorificValue
+		}
 
 		// Detect gas anomalies
-		anomalies := a.detectAnomalies(data.GasConsumption, "gas")
+		anomalies := detector.Detect(data.GasConsumption, "gas")
 		result.Anomalies = append(result.Anomalies, anomalies...)
 	}
 
-	// Calculate total average daily cost (import - export + gas)
-	result.AvgDailyCostTotal = result.AvgDailyCostElectricity - result.AvgDailyEarningsExport + result.AvgDailyCostGas
+	// With several properties, the account's averages are the sum of theirs
+	if len(result.Properties) > 0 {
+		sumPropertyAverages(result)
+		sumPropertyBaseload(result)
+	}
+
+	// Standing charges apply every day regardless of usage
+	result.AvgDailyStandingElectricity = a.calculateAverageStandingCharge(data.ElectricityStandingCharges)
+	result.AvgDailyStandingGas = a.calculateAverageStandingCharge(data.GasStandingCharges)
+
+	// Calculate total average daily cost (import - export + gas + standing charges)
+	result.AvgDailyCostTotal = result.AvgDailyCostElectricity - result.AvgDailyEarningsExport + result.AvgDailyCostGas +
+		result.AvgDailyStandingElectricity + result.AvgDailyStandingGas
 
 	// Calculate projected monthly cost
 	result.ProjectedMonthlyCost = result.AvgDailyCostTotal * 30
 
+	// Track spend against the configured budget
+	a.logger.LogAnalysisStage("budget_tracking")
+	result.Budget = a.trackBudget(data)
+
 	// Calculate Direct Debit recommendation
 	a.logger.LogAnalysisStage("direct_debit_recommendation")
-	result.RecommendedDirectDebit = a.calculateRecommendedDirectDebit(result.AvgDailyCostTotal)
+	result.Forecast = a.forecastDirectDebit(result)
+	result.RecommendedDirectDebit = result.Forecast.RecommendedDirectDebit
 	result.PaymentStatus = a.determinePaymentStatus(result.RecommendedDirectDebit, result.CurrentDirectDebit)
 
 	// Detect tariff changes
@@ -144,15 +239,16 @@ func (a *Analyzer) Analyze(data *CollectedData) (*AnalysisResult, error) {
 	// Enrich anomalies with weather data
 	if len(result.Anomalies) > 0 {
 		a.logger.LogAnalysisStage("weather_enrichment")
-		a.enrichAnomaliesWithWeather(result.Anomalies)
+		a.enrichAnomaliesWithWeather(ctx, result.Anomalies, weather)
 
 		// Filter out weather-expected anomalies
-		result.Anomalies = a.filterWeatherExpectedAnomalies(result.Anomalies)
+		result.Anomalies = a.filterWeatherExpectedAnomalies(result.Anomalies, result.HeatingModels)
 	}
 
 	// Generate insights
 	a.logger.LogAnalysisStage("insights_generation")
 	result.Insights = a.generateInsights(result, data)
+	result.Insights = a.applyDataConfidence(result.Insights, result)
 
 	// Generate charts
 	a.logger.LogAnalysisStage("chart_generation")
@@ -165,24 +261,72 @@ func (a *Analyzer) Analyze(data *CollectedData) (*AnalysisResult, error) {
 		a.logger.Warn("Failed to generate daily usage chart", "error", err)
 	}
 
-	if costChart, err := chartGen.GenerateDailyCostChart(data); err == nil {
+	if costChart, err := chartGen.GenerateDailyCostChart(data, result.Budget); err == nil {
 		result.DailyCostChart = costChart
 		a.logger.Info("Generated daily cost chart")
 	} else {
 		a.logger.Warn("Failed to generate daily cost chart", "error", err)
 	}
 
+	if result.LoadProfile != nil {
+		if profileChart, err := chartGen.GenerateLoadProfileChart(result.LoadProfile); err == nil {
+			result.LoadProfileChart = profileChart
+			a.logger.Info("Generated load profile chart")
+		} else {
+			a.logger.Warn("Failed to generate load profile chart", "error", err)
+		}
+	}
+
+	if result.Forecast != nil && len(result.Forecast.Months) > 0 {
+		if forecastChart, err := chartGen.GenerateBalanceForecastChart(result.Forecast); err == nil {
+			result.BalanceForecastChart = forecastChart
+			a.logger.Info("Generated balance forecast chart")
+		} else {
+			a.logger.Warn("Failed to generate balance forecast chart", "error", err)
+		}
+	}
+
 	a.logger.Info("Analysis completed",
 		"anomalies", len(result.Anomalies),
 		"tariff_changes", len(result.TariffChanges),
 		"insights", len(result.Insights),
+		"properties", len(result.Properties),
 	)
 
 	return result, nil
 }
 
+// analyzeProperty analyses a single property's data. A property that can't be
+// analysed (for example, one with no consumption yet) still gets a result so it
+// appears in the report.
+func (a *Analyzer) analyzeProperty(ctx context.Context, data *CollectedData) *AnalysisResult {
+	label := Property{ID: data.PropertyID, Address: data.PropertyAddress}.Label()
+	a.logger.Info("Analysing property", "property", label)
+
+	result, err := a.Analyze(ctx, data)
+	if err != nil {
+		a.logger.Warn("Failed to analyse property", "property", label, "error", err)
+		return &AnalysisResult{
+			GeneratedAt:     now(),
+			PropertyID:      data.PropertyID,
+			PropertyAddress: data.PropertyAddress,
+		}
+	}
+
+	// Charts are only drawn for the account as a whole
+	result.DailyUsageChart = ""
+	result.DailyCostChart = ""
+	result.LoadProfileChart = ""
+	result.BalanceForecastChart = ""
+
+	// Budgets are tracked for the account as a whole
+	result.Budget = nil
+	return result
+}
+
 // calculateAverageConsumption calculates average daily consumption in kWh
-func (a *Analyzer) calculateAverageConsumption(consumptions []Consumption) float64 {
+// over the days the readings cover
+func (a *Analyzer) calculateAverageConsumption(consumptions []Consumption, daysCovered float64) float64 {
 	if len(consumptions) == 0 {
 		return 0
 	}
@@ -192,12 +336,13 @@ func (a *Analyzer) calculateAverageConsumption(consumptions []Consumption) float
 		total += c.Value
 	}
 
-	// Divide by number of days in analysis period, not number of records
-	return total / float64(a.config.AnalysisPeriodDays)
+	// Divide by days with readings, not number of records or the whole period
+	return averageOverCoverage(total, daysCovered, a.config.AnalysisPeriodDays)
 }
 
-// calculateAverageCost calculates average daily cost in pounds
-func (a *Analyzer) calculateAverageCost(consumptions []Consumption) float64 {
+// calculateAverageCost calculates average daily cost in pounds over the days
+// the readings cover
+func (a *Analyzer) calculateAverageCost(consumptions []Consumption, daysCovered float64) float64 {
 	if len(consumptions) == 0 {
 		return 0
 	}
@@ -207,130 +352,165 @@ func (a *Analyzer) calculateAverageCost(consumptions []Consumption) float64 {
 		total += c.Cost
 	}
 
-	// Convert pence to pounds and divide by number of days in analysis period
-	return (total / 100.0) / float64(a.config.AnalysisPeriodDays)
+	// Convert pence to pounds and divide by days with readings
+	return averageOverCoverage(total/100.0, daysCovered, a.config.AnalysisPeriodDays)
 }
 
-// calculateRecommendedDirectDebit calculates the recommended monthly Direct Debit amount
-// This considers seasonal variations and provides a year-round stable payment
-func (a *Analyzer) calculateRecommendedDirectDebit(avgDailyCost float64) float64 {
-	// Base monthly cost from current daily average
-	baseMonthlyCost := avgDailyCost * 30
+// assessCoverage measures each of a property's meters against the analysis period
+func (a *Analyzer) assessCoverage(data *CollectedData, start, end time.Time) []DataCoverage {
+	var coverage []DataCoverage
+	for _, series := range []struct {
+		fuelType     string
+		consumptions []Consumption
+	}{
+		{"electricity", data.ElectricityConsumption},
+		{"export", data.ElectricityExport},
+		{"gas", data.GasConsumption},
+	} {
+		if len(series.consumptions) == 0 {
+			continue
+		}
 
-	// Apply seasonal adjustment based on current month
-	currentMonth := time.Now().Month()
-	seasonalMultiplier := 1.0
+		c := AssessCoverage(series.fuelType, series.consumptions, start, end)
+		a.logger.Info("Assessed data coverage",
+			"fuel", c.FuelType,
+			"coverage_percent", fmt.Sprintf("%.1f", c.CoveragePercent),
+			"days_covered", fmt.Sprintf("%.1f", c.DaysCovered),
+			"missing_slots", c.MissingSlots,
+			"duplicate_slots", c.DuplicateSlots,
+			"gaps", c.GapCount,
+		)
+		coverage = append(coverage, c)
+	}
+	return coverage
+}
 
-	// Winter months (Nov-Feb): expect 30-50% higher usage due to heating
-	if currentMonth >= 11 || currentMonth <= 2 {
-		seasonalMultiplier = 1.40 // Assume 40% increase for winter
-	} else if currentMonth >= 3 && currentMonth <= 4 {
-		// Spring transition (Mar-Apr): moderate increase
-		seasonalMultiplier = 1.20
-	} else if currentMonth >= 9 && currentMonth <= 10 {
-		// Autumn transition (Sep-Oct): moderate increase
-		seasonalMultiplier = 1.20
-	} else {
-		// Summer months (May-Aug): lower usage, base rate
-		seasonalMultiplier = 1.0
+// coveredReadings returns the readings to average and the days they cover.
+// Repeated readings for a slot are dropped. The account rollup's readings are
+// pooled from several properties, so they're returned as they are and its
+// averages are replaced by the sum of its properties' afterwards.
+func (a *Analyzer) coveredReadings(data *CollectedData, consumptions []Consumption, coverage []DataCoverage, fuelType string) ([]Consumption, float64) {
+	if len(data.Properties) > 0 {
+		return consumptions, 0
 	}
 
-	// Calculate seasonally-adjusted annual cost
-	// This averages out the seasonal variation for stable monthly payments
-	annualEstimate := baseMonthlyCost * 12 * seasonalMultiplier
-	recommendedMonthly := annualEstimate / 12
+	for _, c := range coverage {
+		if c.FuelType == fuelType {
+			return dedupeConsumption(consumptions, time.Duration(c.SlotMinutes)*time.Minute), c.DaysCovered
+		}
+	}
+	return consumptions, 0
+}
 
-	// Add 10% buffer for unexpected variations
-	recommendedMonthly *= 1.10
+// sumPropertyAverages sets the account's daily averages to the sum of its properties'
+func sumPropertyAverages(result *AnalysisResult) {
+	result.AvgDailyElectricity, result.AvgDailyCostElectricity = 0, 0
+	result.AvgDailyExport, result.AvgDailyEarningsExport = 0, 0
+	result.AvgDailyGas, result.AvgDailyCostGas, result.AvgDailyGasVolume = 0, 0, 0
+
+	for _, property := range result.Properties {
+		result.AvgDailyElectricity += property.AvgDailyElectricity
+		result.AvgDailyCostElectricity += property.AvgDailyCostElectricity
+		result.AvgDailyExport += property.AvgDailyExport
+		result.AvgDailyEarningsExport += property.AvgDailyEarningsExport
+		result.AvgDailyGas += property.AvgDailyGas
+		result.AvgDailyCostGas += property.AvgDailyCostGas
+		result.AvgDailyGasVolume += property.AvgDailyGasVolume
+	}
 
-	// Round to nearest £5 for practical payment amounts
-	return math.Round(recommendedMonthly/5) * 5
+	// Volume is only meaningful when every property's gas meter reports it
+	if result.GasUnit != GasUnitCubicMetres {
+		result.AvgDailyGasVolume = 0
+	}
 }
 
-// determinePaymentStatus determines if the user is underpaying, overpaying, or balanced
-func (a *Analyzer) determinePaymentStatus(recommended, current float64) string {
-	if current == 0 {
-		return "Unknown"
+// propertyCoverage lists every property's coverage, labelled with the property
+func propertyCoverage(properties []*AnalysisResult) []DataCoverage {
+	var coverage []DataCoverage
+	for _, property := range properties {
+		for _, c := range property.DataCoverage {
+			c.Property = property.PropertyLabel()
+			coverage = append(coverage, c)
+		}
 	}
+	return coverage
+}
 
-	difference := math.Abs(recommended - current)
-
-	if difference < 5 {
-		return "Balanced"
+// calculateAverageStandingCharge calculates the average daily standing charge in pounds
+func (a *Analyzer) calculateAverageStandingCharge(charges []DailyStandingCharge) float64 {
+	if len(charges) == 0 {
+		return 0
 	}
 
-	if recommended > current {
-		return "Underpaying"
+	total := 0.0
+	for _, c := range charges {
+		total += c.Charge
 	}
 
-	return "Overpaying"
+	// Convert pence to pounds and average over the days charged
+	return (total / 100.0) / float64(len(charges))
 }
 
-// detectAnomalies detects unusual consumption patterns on daily aggregated data
-func (a *Analyzer) detectAnomalies(consumptions []Consumption, fuelType string) []Anomaly {
-	if len(consumptions) < 7 {
-		// Need at least a week of data for meaningful anomaly detection
-		return nil
+// resolveDirectDebit sets the current Direct Debit from the configured amount,
+// falling back to the account's payment schedule when none is configured, and
+// records what the account collects when the two disagree
+func (a *Analyzer) resolveDirectDebit(result *AnalysisResult, schedule *PaymentSchedule) {
+	configured := a.config.DirectDebitAmount
+	result.CurrentDirectDebit = configured
+	if configured > 0 {
+		result.DirectDebitSource = DirectDebitSourceConfig
 	}
 
-	// Aggregate consumption to daily totals
-	dailyConsumption := aggregateToDaily(consumptions)
+	if schedule == nil {
+		return
+	}
+	result.PaymentSchedule = schedule
 
-	if len(dailyConsumption) < 7 {
-		return nil
+	monthly := schedule.MonthlyAmount()
+	if schedule.IsVariable || monthly <= 0 {
+		a.logger.Info("Payment schedule has no fixed amount, using configured Direct Debit",
+			"frequency", schedule.Frequency,
+			"variable", schedule.IsVariable,
+		)
+		return
 	}
 
-	var anomalies []Anomaly
+	if configured == 0 {
+		result.CurrentDirectDebit = monthly
+		result.DirectDebitSource = DirectDebitSourceAccount
+		return
+	}
 
-	// Extract daily values for statistical analysis
-	values := make([]float64, 0, len(dailyConsumption))
-	for _, daily := range dailyConsumption {
-		values = append(values, daily.Value)
+	if math.Abs(configured-monthly) > directDebitMismatchTolerance {
+		result.ScheduledDirectDebit = monthly
+		a.logger.Warn("Configured Direct Debit does not match the account's payment schedule",
+			"configured", configured,
+			"account", monthly,
+		)
 	}
+}
 
-	// Calculate mean and standard deviation
-	mean := calculateMean(values)
-	stdDev := calculateStdDev(values, mean)
+// determinePaymentStatus determines if the user is underpaying, overpaying, or balanced
+func (a *Analyzer) determinePaymentStatus(recommended, current float64) string {
+	if current == 0 {
+		return "Unknown"
+	}
 
-	// Detect anomalies in daily data
-	for _, daily := range dailyConsumption {
-		// Check for very low usage (< 10% of mean)
-		if daily.Value < mean*0.1 && mean > 0 {
-			anomalies = append(anomalies, Anomaly{
-				Date:             daily.StartAt,
-				FuelType:         fuelType,
-				Type:             "low_usage",
-				Description:      fmt.Sprintf("Unusually low %s usage for this day", fuelType),
-				ActualValue:      daily.Value,
-				ExpectedValue:    mean,
-				DeviationPercent: ((daily.Value - mean) / mean) * 100,
-			})
-			continue
-		}
+	difference := math.Abs(recommended - current)
 
-		// Check for consumption spike (> 2 standard deviations above mean)
-		// Note: We'll filter out weather-expected spikes after enriching with weather data
-		if daily.Value > mean+2*stdDev && stdDev > 0 {
-			deviation := ((daily.Value - mean) / mean) * 100
-			if deviation > a.config.AnomalyThreshold {
-				a.logger.LogAnomalyDetected(daily.StartAt.Format("2006-01-02"), "consumption_spike", deviation)
-				anomalies = append(anomalies, Anomaly{
-					Date:             daily.StartAt,
-					FuelType:         fuelType,
-					Type:             "consumption_spike",
-					Description:      fmt.Sprintf("Unusually high %s consumption", fuelType),
-					ActualValue:      daily.Value,
-					ExpectedValue:    mean,
-					DeviationPercent: deviation,
-				})
-			}
-		}
+	if difference < 5 {
+		return "Balanced"
+	}
+
+	if recommended > current {
+		return "Underpaying"
 	}
 
-	return anomalies
+	return "Overpaying"
 }
 
-// aggregateToDaily aggregates half-hourly consumption data to daily totals
+// aggregateToDaily aggregates half-hourly consumption data to daily totals.
+// Days are UK calendar days, whatever offset the readings were returned with.
 func aggregateToDaily(consumptions []Consumption) []Consumption {
 	if len(consumptions) == 0 {
 		return nil
@@ -340,7 +520,7 @@ func aggregateToDaily(consumptions []Consumption) []Consumption {
 
 	for _, c := range consumptions {
 		// Get date key (YYYY-MM-DD)
-		dateKey := c.StartAt.Format("2006-01-02")
+		dateKey := londonDate(c.StartAt)
 
 		if existing, exists := dailyMap[dateKey]; exists {
 			// Add to existing day
@@ -348,10 +528,10 @@ func aggregateToDaily(consumptions []Consumption) []Consumption {
 			existing.Cost += c.Cost
 		} else {
 			// Create new day entry
-			dayStart := time.Date(c.StartAt.Year(), c.StartAt.Month(), c.StartAt.Day(), 0, 0, 0, 0, c.StartAt.Location())
+			dayStart := londonDayStart(c.StartAt)
 			dailyMap[dateKey] = &Consumption{
 				StartAt: dayStart,
-				EndAt:   dayStart.Add(24 * time.Hour),
+				EndAt:   dayStart.AddDate(0, 0, 1),
 				Value:   c.Value,
 				Cost:    c.Cost,
 			}
@@ -438,6 +618,17 @@ func (a *Analyzer) generateInsights(result *AnalysisResult, data *CollectedData)
 		}
 	}
 
+	// Configured Direct Debit may be out of date
+	if result.ScheduledDirectDebit > 0 {
+		insights = append(insights, Insight{
+			Category:    "payment",
+			Priority:    "high",
+			Title:       "Configured Direct Debit Doesn't Match Your Account",
+			Description: fmt.Sprintf("config.yaml says £%.2f per month, but Octopus is collecting £%.2f per month. Recommendations use the configured amount.", result.CurrentDirectDebit, result.ScheduledDirectDebit),
+			Action:      fmt.Sprintf("Update direct_debit_amount in config.yaml to %.2f, or set it to 0 to use the amount Octopus collects", result.ScheduledDirectDebit),
+		})
+	}
+
 	// Balance insights with Direct Debit context
 	if result.CurrentBalance < -50 {
 		insights = append(insights, Insight{
@@ -480,7 +671,7 @@ func (a *Analyzer) generateInsights(result *AnalysisResult, data *CollectedData)
 
 	// Recent anomaly warnings
 	recentAnomalies := 0
-	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
+	sevenDaysAgo := now().AddDate(0, 0, -7)
 	for _, anomaly := range result.Anomalies {
 		if anomaly.Date.After(sevenDaysAgo) && anomaly.Type != "zero_usage" {
 			recentAnomalies++
@@ -497,8 +688,24 @@ func (a *Analyzer) generateInsights(result *AnalysisResult, data *CollectedData)
 		})
 	}
 
+	// Always-on load creeping up
+	if insight, ok := baseloadInsight(result); ok {
+		insights = append(insights, insight)
+	}
+
+	// Heating use after allowing for the weather
+	insights = append(insights, heatingInsights(result)...)
+
+	// Spending against the configured budget
+	insights = append(insights, budgetInsights(result.Budget)...)
+
+	// Moving use into cheaper time-of-use bands
+	if insight, ok := loadShiftInsight(result); ok {
+		insights = append(insights, insight)
+	}
+
 	// Seasonal insights (winter months: November to February)
-	currentMonth := time.Now().Month()
+	currentMonth := now().Month()
 	if currentMonth >= 11 || currentMonth <= 2 {
 		insights = append(insights, Insight{
 			Category:    "seasonal",
@@ -518,6 +725,63 @@ func (a *Analyzer) generateInsights(result *AnalysisResult, data *CollectedData)
 	return insights
 }
 
+// applyDataConfidence marks insights drawn from incomplete meter data and adds
+// insights explaining what's missing
+func (a *Analyzer) applyDataConfidence(insights []Insight, result *AnalysisResult) []Insight {
+	var quality []Insight
+
+	for _, c := range result.DataCoverage {
+		if c.Stale() {
+			last := "no readings were received"
+			if !c.LastReading.IsZero() {
+				last = "the last reading was for " + c.LastReading.In(london).Format("2 January 2006 15:04")
+			}
+			quality = append(quality, Insight{
+				Category:    "data",
+				Priority:    "medium",
+				Title:       fmt.Sprintf("%s Readings Are Out of Date", c.Label()),
+				Description: fmt.Sprintf("Your %s meter hasn't reported recently - %s.", strings.ToLower(fuelLabel(c.FuelType)), last),
+				Action:      "Check your smart meter's in-home display is connected. If readings don't resume within a few days, contact Octopus Energy",
+			})
+		}
+	}
+
+	if result.DataConfidence == ConfidenceHigh || result.DataConfidence == "" {
+		return append(insights, quality...)
+	}
+
+	// Everything else is based on averages, which are only as good as the data
+	for i := range insights {
+		insights[i].Confidence = result.DataConfidence
+	}
+
+	lowest := result.DataCoverage[0]
+	for _, c := range result.DataCoverage[1:] {
+		if c.CoveragePercent < lowest.CoveragePercent {
+			lowest = c
+		}
+	}
+
+	priority := "medium"
+	if result.DataConfidence == ConfidenceLow {
+		priority = "high"
+	}
+	quality = append([]Insight{{
+		Category:    "data",
+		Priority:    priority,
+		Title:       "Incomplete Meter Data",
+		Description: fmt.Sprintf("Readings cover %.1f of the %d days analysed for %s (%.0f%%). Averages use only the days with readings, but recommendations are less certain.", lowest.DaysCovered, result.AnalysisPeriodDays, strings.ToLower(lowest.Label()), lowest.CoveragePercent),
+		Action:      "See the Data Quality section for the gaps. Re-run the analysis once more readings have arrived before changing your Direct Debit",
+	}}, quality...)
+
+	a.logger.Warn("Meter data is incomplete, insights marked with reduced confidence",
+		"confidence", result.DataConfidence,
+		"lowest_coverage_percent", fmt.Sprintf("%.1f", lowest.CoveragePercent),
+	)
+
+	return append(insights, quality...)
+}
+
 // generateExportInsights generates insights specific to solar/battery export performance
 func (a *Analyzer) generateExportInsights(result *AnalysisResult, data *CollectedData) []Insight {
 	var insights []Insight
@@ -605,7 +869,7 @@ func (a *Analyzer) generateExportInsights(result *AnalysisResult, data *Collecte
 	}
 
 	// Insight 4: Seasonal Considerations
-	currentMonth := time.Now().Month()
+	currentMonth := now().Month()
 	if currentMonth >= 10 || currentMonth <= 3 {
 		// Winter months - lower solar generation expected
 		insights = append(insights, Insight{
@@ -657,53 +921,69 @@ func calculateMean(values []float64) float64 {
 	return sum / float64(len(values))
 }
 
-// calculateStdDev calculates the standard deviation
-func calculateStdDev(values []float64, mean float64) float64 {
-	if len(values) == 0 {
-		return 0
+// fetchPeriodWeather fetches weather for every day from start to end, keyed by
+// date. It returns nil when weather isn't available.
+func (a *Analyzer) fetchPeriodWeather(ctx context.Context, start, end time.Time) map[string]*WeatherData {
+	if a.weatherClient == nil {
+		return nil
 	}
 
-	sumSquaredDiff := 0.0
-	for _, v := range values {
-		diff := v - mean
-		sumSquaredDiff += diff * diff
+	// Properties and their rollup share a period, so fetch it once
+	key := londonDate(start) + "/" + londonDate(end)
+	if weatherMap, ok := a.periodWeather[key]; ok {
+		return weatherMap
 	}
 
-	variance := sumSquaredDiff / float64(len(values))
-	return math.Sqrt(variance)
-}
+	weatherMap, err := a.weatherClient.FetchWeatherForDates(ctx, []time.Time{start, end})
+	if err != nil {
+		// Non-fatal - weather-based analysis is skipped
+		return nil
+	}
 
-// enrichAnomaliesWithWeather fetches weather data for anomaly dates and adds context
-func (a *Analyzer) enrichAnomaliesWithWeather(anomalies []Anomaly) {
-	// Extract unique dates from anomalies
-	dates := make([]time.Time, len(anomalies))
-	for i, anomaly := range anomalies {
-		dates[i] = anomaly.Date
+	if a.periodWeather == nil {
+		a.periodWeather = make(map[string]map[string]*WeatherData)
 	}
+	a.periodWeather[key] = weatherMap
+	return weatherMap
+}
 
-	// Fetch weather data for all anomaly dates
-	weatherMap, err := a.weatherClient.FetchWeatherForDates(dates)
-	if err != nil || weatherMap == nil {
-		// Non-fatal - continue without weather data
-		return
+// enrichAnomaliesWithWeather adds weather context to anomalies, using the
+// period's weather when it has already been fetched
+func (a *Analyzer) enrichAnomaliesWithWeather(ctx context.Context, anomalies []Anomaly, weatherMap map[string]*WeatherData) {
+	if weatherMap == nil {
+		// Extract unique dates from anomalies
+		dates := make([]time.Time, len(anomalies))
+		for i, anomaly := range anomalies {
+			dates[i] = anomaly.Date
+		}
+
+		// Fetch weather data for all anomaly dates
+		fetched, err := a.weatherClient.FetchWeatherForDates(ctx, dates)
+		if err != nil || fetched == nil {
+			// Non-fatal - continue without weather data
+			return
+		}
+		weatherMap = fetched
 	}
 
 	// Enrich each anomaly with weather data
 	for i := range anomalies {
-		dateKey := anomalies[i].Date.Format("2006-01-02")
+		dateKey := londonDate(anomalies[i].Date)
 		if weather, found := weatherMap[dateKey]; found {
 			anomalies[i].Weather = weather
 		}
 	}
 }
 
-// filterWeatherExpectedAnomalies removes anomalies that are explained by weather conditions
-func (a *Analyzer) filterWeatherExpectedAnomalies(anomalies []Anomaly) []Anomaly {
+// filterWeatherExpectedAnomalies removes spikes that the property's own
+// degree-day model expects for the day's weather
+func (a *Analyzer) filterWeatherExpectedAnomalies(anomalies []Anomaly, models []HeatingModel) []Anomaly {
 	filtered := make([]Anomaly, 0, len(anomalies))
 
 	for _, anomaly := range anomalies {
-		// Only filter consumption spikes, not low usage
-		if anomaly.Type != "consumption_spike" {
+		// Only filter daily consumption spikes, not low usage. The weather
+		// model already expects more usage on colder days.
+		if anomaly.Type != "consumption_spike" || anomaly.Model == AnomalyModelWeather {
 			filtered = append(filtered, anomaly)
 			continue
 		}
@@ -714,33 +994,23 @@ func (a *Analyzer) filterWeatherExpectedAnomalies(anomalies []Anomaly) []Anomaly
 			continue
 		}
 
-		// Check if gas consumption spike is explained by cold weather
-		if anomaly.FuelType == "gas" {
-			// Gas usage naturally increases in cold weather
-			// If temp < 10°C, this is expected behavior, not an anomaly
-			if anomaly.Weather.TempMean < 10.0 {
-				a.logger.Debug("Filtering weather-expected gas spike",
-					"date", anomaly.Date.Format("2006-01-02"),
-					"temp", anomaly.Weather.TempMean,
-					"usage", anomaly.ActualValue)
-				continue // Skip this anomaly
-			}
+		// Keep it unless this fuel's usage follows the weather
+		model, ok := heatingModel(models, anomaly.FuelType)
+		if !ok {
+			filtered = append(filtered, anomaly)
+			continue
 		}
 
-		// Check if electricity consumption spike is explained by extreme weather
-		if anomaly.FuelType == "electricity" {
-			// Electricity may spike during very cold weather (heating) or very hot (cooling)
-			// Only filter if temperature is extreme AND usage increase is proportional
-			if anomaly.Weather.TempMean < 5.0 || anomaly.Weather.TempMean > 28.0 {
-				// Allow moderate increases for extreme weather, but flag massive spikes
-				if anomaly.DeviationPercent < 100.0 {
-					a.logger.Debug("Filtering weather-expected electricity spike",
-						"date", anomaly.Date.Format("2006-01-02"),
-						"temp", anomaly.Weather.TempMean,
-						"deviation", anomaly.DeviationPercent)
-					continue // Skip this anomaly
-				}
-			}
+		// Usage within the anomaly threshold of what the weather predicts is expected
+		expected := model.ExpectedKWh(anomaly.Weather.TempMean)
+		if expected > 0 && anomaly.ActualValue <= expected*(1+a.config.AnomalyThreshold/100) {
+			a.logger.Debug("Filtering weather-expected spike",
+				"date", londonDate(anomaly.Date),
+				"fuel", anomaly.FuelType,
+				"temp", anomaly.Weather.TempMean,
+				"usage", anomaly.ActualValue,
+				"expected", expected)
+			continue // Skip this anomaly
 		}
 
 		// Keep this anomaly
@@ -0,0 +1,481 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Anomaly detection models. Each works out what a day (or half-hour) would
+// normally use and how much it normally varies, using the median and median
+// absolute deviation so one extreme day doesn't hide the others.
+const (
+	AnomalyModelRolling    = "rolling"     // The preceding weeks, so seasons don't mask each other
+	AnomalyModelWeekday    = "weekday"     // Other days falling on the same day of the week
+	AnomalyModelRobust     = "robust"      // Every other day in the period
+	AnomalyModelWeather    = "weather"     // Expected usage for the day's heating degree-days
+	AnomalyModelHalfHourly = "half_hourly" // The same half-hour on preceding days
+)
+
+// DefaultAnomalyModels is every detection model, used when anomaly_models is empty
+var DefaultAnomalyModels = []string{
+	AnomalyModelRolling,
+	AnomalyModelWeekday,
+	AnomalyModelRobust,
+	AnomalyModelWeather,
+	AnomalyModelHalfHourly,
+}
+
+const (
+	// DefaultAnomalyWindowDays is the length of the rolling baseline
+	DefaultAnomalyWindowDays = 28
+
+	// DefaultAnomalyScore is the robust z-score above which usage is unusual
+	DefaultAnomalyScore = 3.5
+
+	// madScale converts a median absolute deviation to a standard deviation
+	madScale = 1.4826
+
+	// lowUsageFraction of the expected value or less counts as unusually low
+	lowUsageFraction = 0.1
+
+	// minBaselineDays is the fewest comparison days a daily model needs
+	minBaselineDays = 7
+
+	// minWeekdaySamples is the fewest same-weekday days the weekday model needs
+	minWeekdaySamples = 3
+
+	// completeDayShare of a day's slots must have readings for it to be scored
+	completeDayShare = 0.9
+)
+
+// minIntervalExcess is the smallest half-hourly rise (kWh) worth reporting,
+// so that noise on an almost idle meter isn't flagged
+var minIntervalExcess = map[string]float64{
+	"electricity": 0.5,
+	"gas":         1.0,
+}
+
+// AnomalyDetector scores daily totals and half-hourly readings against the
+// enabled baseline models
+type AnomalyDetector struct {
+	models     map[string]bool
+	windowDays int
+	minScore   float64 // Robust z-score above which usage is unusual
+	threshold  float64 // Minimum deviation (%) from expected for a spike
+	weather    map[string]*WeatherData
+	logger     *Logger
+}
+
+// NewAnomalyDetector creates a detector from the anomaly settings in config.
+// Weather is keyed by date (YYYY-MM-DD) and may be nil, which disables the
+// weather model.
+func NewAnomalyDetector(config *Config, weather map[string]*WeatherData, logger *Logger) *AnomalyDetector {
+	models := make(map[string]bool)
+	names := config.AnomalyModels
+	if len(names) == 0 {
+		names = DefaultAnomalyModels
+	}
+	for _, name := range names {
+		models[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	windowDays := config.AnomalyWindowDays
+	if windowDays <= 0 {
+		windowDays = DefaultAnomalyWindowDays
+	}
+	score := config.AnomalyScore
+	if score <= 0 {
+		score = DefaultAnomalyScore
+	}
+
+	return &AnomalyDetector{
+		models:     models,
+		windowDays: windowDays,
+		minScore:   score,
+		threshold:  config.AnomalyThreshold,
+		weather:    weather,
+		logger:     logger,
+	}
+}
+
+// Disable turns off one of the detector's models
+func (d *AnomalyDetector) Disable(model string) {
+	delete(d.models, model)
+}
+
+// baseline is what a model expects for a day or slot, and how much it varies
+type baseline struct {
+	model    string
+	expected float64
+	scale    float64
+}
+
+// Detect finds unusual days and half-hours in one fuel's readings
+func (d *AnomalyDetector) Detect(consumptions []Consumption, fuelType string) []Anomaly {
+	days := completeDays(consumptions)
+	if len(days) < minBaselineDays {
+		// Need at least a week of data for meaningful anomaly detection
+		return nil
+	}
+
+	anomalies := d.detectDaily(days, fuelType)
+	if d.models[AnomalyModelHalfHourly] {
+		// A day that's unusual as a whole already explains its busiest half-hour
+		flagged := make(map[string]bool, len(anomalies))
+		for _, anomaly := range anomalies {
+			flagged[londonDate(anomaly.Date)] = true
+		}
+		for _, anomaly := range d.detectHalfHourly(consumptions, days, fuelType) {
+			if !flagged[londonDate(anomaly.Date)] {
+				anomalies = append(anomalies, anomaly)
+			}
+		}
+	}
+
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		return anomalies[i].Date.Before(anomalies[j].Date)
+	})
+	return anomalies
+}
+
+// detectDaily scores each day's total against every enabled daily model and
+// reports the model that found it most unusual
+func (d *AnomalyDetector) detectDaily(days []Consumption, fuelType string) []Anomaly {
+	values := make([]float64, len(days))
+	for i, day := range days {
+		values[i] = day.Value
+	}
+
+	var weatherFit *degreeDayFit
+	if d.models[AnomalyModelWeather] {
+		weatherFit = fitDegreeDays(days, d.weather)
+	}
+
+	var anomalies []Anomaly
+	for i, day := range days {
+		var baselines []baseline
+		if d.models[AnomalyModelRolling] {
+			if b, ok := d.rollingBaseline(days, i); ok {
+				baselines = append(baselines, b)
+			}
+		}
+		if d.models[AnomalyModelWeekday] {
+			if b, ok := weekdayBaseline(days, i); ok {
+				baselines = append(baselines, b)
+			}
+		}
+		if d.models[AnomalyModelRobust] {
+			if b, ok := robustBaseline(AnomalyModelRobust, without(values, i), minBaselineDays); ok {
+				baselines = append(baselines, b)
+			}
+		}
+		if weatherFit != nil {
+			if b, ok := weatherFit.baseline(day.StartAt, d.weather); ok {
+				baselines = append(baselines, b)
+			}
+		}
+
+		if anomaly, ok := d.score(day, baselines, fuelType); ok {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+
+	return anomalies
+}
+
+// score checks a day against its baselines, returning an anomaly when any
+// model flags it
+func (d *AnomalyDetector) score(day Consumption, baselines []baseline, fuelType string) (Anomaly, bool) {
+	var flagged []string
+	var best baseline
+	bestScore := 0.0
+	low := false
+
+	for _, b := range baselines {
+		if b.expected <= 0 {
+			continue
+		}
+
+		z := (day.Value - b.expected) / b.scale
+		deviation := (day.Value - b.expected) / b.expected * 100
+
+		isLow := day.Value < b.expected*lowUsageFraction
+		isSpike := z >= d.minScore && deviation > d.threshold
+		if !isLow && !isSpike {
+			continue
+		}
+
+		flagged = append(flagged, b.model)
+		if math.Abs(z) > math.Abs(bestScore) {
+			best, bestScore, low = b, z, isLow
+		}
+	}
+
+	if len(flagged) == 0 {
+		return Anomaly{}, false
+	}
+
+	anomaly := Anomaly{
+		Date:             day.StartAt,
+		FuelType:         fuelType,
+		ActualValue:      day.Value,
+		ExpectedValue:    best.expected,
+		DeviationPercent: (day.Value - best.expected) / best.expected * 100,
+		Model:            best.model,
+		Models:           flagged,
+		Score:            bestScore,
+	}
+	if low {
+		anomaly.Type = "low_usage"
+		anomaly.Description = fmt.Sprintf("Unusually low %s usage for this day", fuelType)
+	} else {
+		anomaly.Type = "consumption_spike"
+		anomaly.Description = fmt.Sprintf("Unusually high %s consumption", fuelType)
+		d.logger.LogAnomalyDetected(londonDate(day.StartAt), "consumption_spike", anomaly.DeviationPercent)
+	}
+	return anomaly, true
+}
+
+// rollingBaseline compares a day with the window of days before it
+func (d *AnomalyDetector) rollingBaseline(days []Consumption, i int) (baseline, bool) {
+	windowStart := days[i].StartAt.AddDate(0, 0, -d.windowDays)
+	var values []float64
+	for j := i - 1; j >= 0 && !days[j].StartAt.Before(windowStart); j-- {
+		values = append(values, days[j].Value)
+	}
+	return robustBaseline(AnomalyModelRolling, values, minBaselineDays)
+}
+
+// weekdayBaseline compares a day with other days on the same day of the week
+func weekdayBaseline(days []Consumption, i int) (baseline, bool) {
+	weekday := days[i].StartAt.In(london).Weekday()
+	var values []float64
+	for j, day := range days {
+		if j != i && day.StartAt.In(london).Weekday() == weekday {
+			values = append(values, day.Value)
+		}
+	}
+	return robustBaseline(AnomalyModelWeekday, values, minWeekdaySamples)
+}
+
+// robustBaseline uses the median of values as the expected value and their
+// median absolute deviation as the spread
+func robustBaseline(model string, values []float64, minSamples int) (baseline, bool) {
+	if len(values) < minSamples {
+		return baseline{}, false
+	}
+
+	median := calculateMedian(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+
+	return baseline{
+		model:    model,
+		expected: median,
+		scale:    spreadFloor(madScale*calculateMedian(deviations), median),
+	}, true
+}
+
+// spreadFloor stops a near-constant baseline making every small change look
+// extreme, by never letting the spread fall below 5% of the expected value
+func spreadFloor(scale, expected float64) float64 {
+	return math.Max(scale, math.Max(math.Abs(expected)*0.05, 0.01))
+}
+
+// baseline returns the expected usage for a day from its weather
+func (f *degreeDayFit) baseline(day time.Time, weather map[string]*WeatherData) (baseline, bool) {
+	w, ok := weather[londonDate(day)]
+	if !ok {
+		return baseline{}, false
+	}
+
+	expected := f.expected(w.TempMean)
+	return baseline{
+		model:    AnomalyModelWeather,
+		expected: expected,
+		scale:    spreadFloor(f.scale, expected),
+	}, true
+}
+
+// detectHalfHourly compares each half-hour with the same time of day over the
+// preceding window, reporting at most the most unusual half-hour per day
+func (d *AnomalyDetector) detectHalfHourly(consumptions []Consumption, days []Consumption, fuelType string) []Anomaly {
+	complete := make(map[string]bool, len(days))
+	for _, day := range days {
+		complete[londonDate(day.StartAt)] = true
+	}
+
+	// Group readings by time of day, in date order
+	bySlot := make(map[int][]Consumption)
+	for _, c := range consumptions {
+		local := c.StartAt.In(london)
+		minute := local.Hour()*60 + local.Minute()
+		bySlot[minute] = append(bySlot[minute], c)
+	}
+
+	minExcess := minIntervalExcess[fuelType]
+	worst := make(map[string]Anomaly)
+	for _, readings := range bySlot {
+		sortConsumption(readings)
+		for i, c := range readings {
+			date := londonDate(c.StartAt)
+			if !complete[date] {
+				continue
+			}
+
+			windowStart := c.StartAt.AddDate(0, 0, -d.windowDays)
+			var values []float64
+			for j := i - 1; j >= 0 && !readings[j].StartAt.Before(windowStart); j-- {
+				values = append(values, readings[j].Value)
+			}
+			b, ok := robustBaseline(AnomalyModelHalfHourly, values, minBaselineDays)
+			if !ok || c.Value-b.expected < minExcess {
+				continue
+			}
+
+			z := (c.Value - b.expected) / b.scale
+			deviation := 100.0
+			if b.expected > 0 {
+				deviation = (c.Value - b.expected) / b.expected * 100
+			}
+			if z < d.minScore || deviation <= d.threshold {
+				continue
+			}
+
+			if existing, ok := worst[date]; ok && existing.Score >= z {
+				continue
+			}
+			worst[date] = Anomaly{
+				Date:             c.StartAt,
+				FuelType:         fuelType,
+				Type:             "interval_spike",
+				Description:      fmt.Sprintf("Unusually high %s use at %s", fuelType, c.StartAt.In(london).Format("15:04")),
+				ActualValue:      c.Value,
+				ExpectedValue:    b.expected,
+				DeviationPercent: deviation,
+				Model:            AnomalyModelHalfHourly,
+				Models:           []string{AnomalyModelHalfHourly},
+				Score:            z,
+			}
+		}
+	}
+
+	anomalies := make([]Anomaly, 0, len(worst))
+	for _, anomaly := range worst {
+		anomalies = append(anomalies, anomaly)
+	}
+	return anomalies
+}
+
+// completeDays aggregates readings to UK calendar days, dropping days with too
+// few readings to compare fairly (such as the partial days at either end of
+// the period)
+func completeDays(consumptions []Consumption) []Consumption {
+	slot := slotLength(consumptions)
+	counts := make(map[string]int)
+	for _, c := range consumptions {
+		counts[londonDate(c.StartAt)]++
+	}
+
+	var days []Consumption
+	for _, day := range aggregateToDaily(consumptions) {
+		expected := float64(day.EndAt.Sub(day.StartAt) / slot)
+		if float64(counts[londonDate(day.StartAt)]) >= expected*completeDayShare {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// calculateMedian returns the median of values without reordering them
+func calculateMedian(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// without returns values with the element at i removed
+func without(values []float64, i int) []float64 {
+	rest := make([]float64, 0, len(values)-1)
+	rest = append(rest, values[:i]...)
+	return append(rest, values[i+1:]...)
+}
+
+// validAnomalyModel reports whether name is a known detection model
+func validAnomalyModel(name string) bool {
+	for _, model := range DefaultAnomalyModels {
+		if model == name {
+			return true
+		}
+	}
+	return false
+}
+
+// anomalyModelLabels names each detection model for reports
+var anomalyModelLabels = map[string]string{
+	AnomalyModelRolling:    "Rolling baseline",
+	AnomalyModelWeekday:    "Day of week",
+	AnomalyModelRobust:     "Period median",
+	AnomalyModelWeather:    "Weather-normalised",
+	AnomalyModelHalfHourly: "Half-hourly",
+}
+
+// anomalyModelLabel describes the model that flagged an anomaly, noting how
+// many others agreed
+func anomalyModelLabel(anomaly Anomaly) string {
+	label, ok := anomalyModelLabels[anomaly.Model]
+	if !ok {
+		label = anomaly.Model
+	}
+	if label == "" {
+		return "-"
+	}
+	if others := len(anomaly.Models) - 1; others > 0 {
+		label += fmt.Sprintf(" (+%d)", others)
+	}
+	return label
+}
+
+// anomalyDateLabel formats when an anomaly happened, including the time for
+// half-hourly spikes
+func anomalyDateLabel(anomaly Anomaly) string {
+	if anomaly.Type == "interval_spike" {
+		return anomaly.Date.In(london).Format("2006-01-02 15:04")
+	}
+	return londonDate(anomaly.Date)
+}
+
+// sortAnomaliesBySignificance orders anomalies with the most unusual first
+func sortAnomaliesBySignificance(anomalies []Anomaly) {
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		return math.Abs(anomalies[i].Score) > math.Abs(anomalies[j].Score)
+	})
+}
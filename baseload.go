@@ -0,0 +1,271 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// baseloadPercentile of a day's half-hours is taken as its baseload, so a
+	// kettle or a short burst of charging doesn't lift it
+	baseloadPercentile = 10
+
+	// minBaseloadSlots is the fewest usable half-hours needed to estimate a day
+	minBaseloadSlots = 12
+
+	// baseloadTrendDays at either end of the period are compared to spot a rise
+	baseloadTrendDays = 14
+
+	// A rise counts as noticeable when it's at least this many watts and this
+	// percentage above where the period started
+	baseloadRiseWatts   = 30.0
+	baseloadRisePercent = 20.0
+
+	// hoursPerYear converts a constant load in kW to kWh a year
+	hoursPerYear = 24 * 365
+)
+
+// EstimateBaseload works out each day's always-on load in watts from its
+// quietest half-hours of electricity import, leaving out any slot skip rejects
+func EstimateBaseload(consumptions []Consumption, skip func(Consumption) bool) []BaseloadDay {
+	slots := make(map[string][]float64)
+	starts := make(map[string]time.Time)
+	for _, c := range consumptions {
+		if skip(c) {
+			continue
+		}
+
+		hours := c.EndAt.Sub(c.StartAt).Hours()
+		if hours <= 0 {
+			continue
+		}
+
+		date := londonDate(c.StartAt)
+		slots[date] = append(slots[date], c.Value/hours*1000)
+		if _, ok := starts[date]; !ok {
+			starts[date] = londonDayStart(c.StartAt)
+		}
+	}
+
+	days := make([]BaseloadDay, 0, len(slots))
+	for date, watts := range slots {
+		if len(watts) < minBaseloadSlots {
+			continue
+		}
+		days = append(days, BaseloadDay{
+			Date:  starts[date],
+			Watts: percentile(watts, baseloadPercentile),
+		})
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+	return days
+}
+
+// analyzeBaseload estimates baseload over the period, how it has moved and
+// what it costs a year at the current electricity rates
+func (a *Analyzer) analyzeBaseload(result *AnalysisResult, data *CollectedData) {
+	days := EstimateBaseload(data.ElectricityConsumption, a.baseloadSkip(data))
+	if len(days) == 0 {
+		return
+	}
+
+	watts := make([]float64, len(days))
+	for i, day := range days {
+		watts[i] = day.Watts
+	}
+
+	result.BaseloadDaily = days
+	result.BaseloadWatts = calculateMedian(watts)
+
+	// Compare the start and end of the period when there's enough to tell them apart
+	if len(watts) >= 2*baseloadTrendDays {
+		result.BaseloadStartWatts = calculateMedian(watts[:baseloadTrendDays])
+		result.BaseloadRecentWatts = calculateMedian(watts[len(watts)-baseloadTrendDays:])
+	}
+
+	// Cost what's running now, rather than what was running months ago
+	result.BaseloadAnnualKWh = currentBaseload(result) / 1000 * hoursPerYear
+	model, _ := a.config.PricingModelOverride()
+	result.BaseloadAnnualCost = result.BaseloadAnnualKWh * averageUnitRate(data.ElectricityAgreements, model) / 100
+}
+
+// baseloadSkip rejects half-hours that can hide or inflate always-on load:
+// the tariff's off-peak band and smart-charge dispatches, when EVs, batteries
+// and storage heaters charge, and slots that exported, when solar covers the home
+func (a *Analyzer) baseloadSkip(data *CollectedData) func(Consumption) bool {
+	override, _ := a.config.PricingModelOverride()
+	exported := make(map[int64]bool)
+	for _, c := range data.ElectricityExport {
+		if c.Value > 0 {
+			exported[c.StartAt.Unix()] = true
+		}
+	}
+
+	return func(c Consumption) bool {
+		if exported[c.StartAt.Unix()] || coveredByDispatch(c.StartAt, c.EndAt, data.Dispatches) {
+			return true
+		}
+		model := override
+		if model == nil {
+			model = DetectPricingModel(findActiveTariff(c.StartAt, data.ElectricityAgreements))
+		}
+		return model.BandAt(c.StartAt) == BandOffPeak
+	}
+}
+
+// sumPropertyBaseload combines each property's baseload into the account's,
+// since pooled readings from several homes can't be split by day
+func sumPropertyBaseload(result *AnalysisResult) {
+	byDate := make(map[string]*BaseloadDay)
+	var dates []string
+	for _, property := range result.Properties {
+		result.BaseloadWatts += property.BaseloadWatts
+		result.BaseloadStartWatts += property.BaseloadStartWatts
+		result.BaseloadRecentWatts += property.BaseloadRecentWatts
+		result.BaseloadAnnualKWh += property.BaseloadAnnualKWh
+		result.BaseloadAnnualCost += property.BaseloadAnnualCost
+
+		for _, day := range property.BaseloadDaily {
+			date := londonDate(day.Date)
+			if existing, ok := byDate[date]; ok {
+				existing.Watts += day.Watts
+				continue
+			}
+			byDate[date] = &BaseloadDay{Date: day.Date, Watts: day.Watts}
+			dates = append(dates, date)
+		}
+	}
+
+	sort.Strings(dates)
+	result.BaseloadDaily = nil
+	for _, date := range dates {
+		result.BaseloadDaily = append(result.BaseloadDaily, *byDate[date])
+	}
+}
+
+// baseloadInsight reports a noticeable rise in always-on load, returning false
+// when baseload has held steady
+func baseloadInsight(result *AnalysisResult) (Insight, bool) {
+	start, recent := result.BaseloadStartWatts, result.BaseloadRecentWatts
+	if start <= 0 || recent <= 0 {
+		return Insight{}, false
+	}
+
+	rise := recent - start
+	if rise < baseloadRiseWatts || rise/start*100 < baseloadRisePercent {
+		return Insight{}, false
+	}
+
+	// Price the extra load using the same rate as the annual baseload cost
+	extraCost := result.BaseloadAnnualCost * rise / recent
+
+	priority := "medium"
+	if rise >= 100 {
+		priority = "high"
+	}
+
+	description := fmt.Sprintf("Your always-on load has risen from %.0f W to %.0f W over the analysis period (+%.0f%%).", start, recent, rise/start*100)
+	if extraCost > 0 {
+		description += fmt.Sprintf(" Left running, the extra %.0f W costs about £%.0f a year at current rates.", rise, extraCost)
+	}
+
+	return Insight{
+		Category:    "usage",
+		Priority:    priority,
+		Title:       "Always-On Usage Has Risen",
+		Description: description,
+		Action:      "Check what's running around the clock - a failing fridge or freezer, a heater, dehumidifier or pump left on, or new equipment on standby",
+	}, true
+}
+
+// currentBaseload is the most recent baseload estimate, falling back to the
+// period's typical baseload when the period is too short to compare
+func currentBaseload(result *AnalysisResult) float64 {
+	if result.BaseloadRecentWatts > 0 {
+		return result.BaseloadRecentWatts
+	}
+	return result.BaseloadWatts
+}
+
+// baseloadWeeks averages daily baseload by week (starting Monday) for reports
+func baseloadWeeks(days []BaseloadDay) []BaseloadDay {
+	var weeks []BaseloadDay
+	var current []float64
+	var weekStart time.Time
+
+	flush := func() {
+		if len(current) > 0 {
+			weeks = append(weeks, BaseloadDay{Date: weekStart, Watts: calculateMean(current)})
+		}
+		current = nil
+	}
+
+	for _, day := range days {
+		local := day.Date.In(london)
+		offset := (int(local.Weekday()) + 6) % 7 // Days since Monday
+		start := time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, london)
+		if !start.Equal(weekStart) {
+			flush()
+			weekStart = start
+		}
+		current = append(current, day.Watts)
+	}
+	flush()
+
+	return weeks
+}
+
+// averageUnitRate returns the current electricity tariff's unit rate (p/kWh)
+// averaged across a day's half-hours, which is what a constant load pays
+func averageUnitRate(agreements []Agreement, model PricingModel) float64 {
+	tariff := currentTariff(agreements)
+	if tariff == nil {
+		return 0
+	}
+	if model == nil {
+		model = DetectPricingModel(tariff)
+	}
+
+	day := londonDayStart(now())
+	total, slots := 0.0, 0
+	for t := day; t.Before(day.AddDate(0, 0, 1)); t = t.Add(defaultSlotLength) {
+		total += tariff.RateForBand(model.BandAt(t))
+		slots++
+	}
+	return total / float64(slots)
+}
+
+// percentile returns the pth percentile of values, interpolating between the
+// nearest ranks
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
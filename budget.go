@@ -0,0 +1,272 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TargetOn returns the daily spend target (pounds) for a day: the configured
+// daily target, or the monthly budget spread over the month's days
+func (b *BudgetTracking) TargetOn(day time.Time) float64 {
+	if b.TargetDailySpend > 0 {
+		return b.TargetDailySpend
+	}
+	return b.MonthlyBudget / float64(daysInMonth(day))
+}
+
+// BudgetFor returns the budget (pounds) for a whole month, before carry-over
+func (b *BudgetTracking) BudgetFor(month time.Time) float64 {
+	if b.MonthlyBudget > 0 {
+		return b.MonthlyBudget
+	}
+	return b.TargetDailySpend * float64(daysInMonth(month))
+}
+
+// CurrentMonth returns this month's budget, the last of the tracked months
+func (b *BudgetTracking) CurrentMonth() BudgetMonth {
+	return b.Months[len(b.Months)-1]
+}
+
+// Available is the month's budget including anything carried over
+func (m BudgetMonth) Available() float64 {
+	return m.Budget + m.CarriedIn
+}
+
+// Remaining is what's left of the month's budget after spending so far
+func (m BudgetMonth) Remaining() float64 {
+	return m.Available() - m.Spent
+}
+
+// trackBudget compares each complete day's net cost with the configured
+// budget, month by month, and projects this month's spend at its pace so far.
+// It returns nil when no budget is configured.
+func (a *Analyzer) trackBudget(data *CollectedData) *BudgetTracking {
+	if a.config.TargetDailySpend <= 0 && a.config.MonthlyBudget <= 0 {
+		return nil
+	}
+
+	budget := &BudgetTracking{
+		TargetDailySpend: a.config.TargetDailySpend,
+		MonthlyBudget:    a.config.MonthlyBudget,
+		CarryOver:        a.config.BudgetCarryOver,
+	}
+
+	spend := dailyNetCosts(data)
+	dates := make([]string, 0, len(spend))
+	for date := range spend {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	// Group days into months, ending with the current one even before it has readings
+	today := londonDayStart(now())
+	thisMonth := monthStart(today)
+	var total float64
+	for _, date := range dates {
+		day, err := time.ParseInLocation("2006-01-02", date, london)
+		if err != nil || !day.Before(today) {
+			continue
+		}
+
+		month := monthStart(day)
+		if n := len(budget.Months); n == 0 || !budget.Months[n-1].Month.Equal(month) {
+			budget.Months = append(budget.Months, BudgetMonth{Month: month, DaysInMonth: daysInMonth(month)})
+		}
+		m := &budget.Months[len(budget.Months)-1]
+		m.Days++
+		m.Spent += spend[date]
+		if spend[date] > budget.TargetOn(day) {
+			m.DaysOver++
+			budget.DaysOverBudget++
+		}
+		budget.DaysTracked++
+		total += spend[date]
+	}
+	if n := len(budget.Months); n == 0 || !budget.Months[n-1].Month.Equal(thisMonth) {
+		budget.Months = append(budget.Months, BudgetMonth{Month: thisMonth, DaysInMonth: daysInMonth(thisMonth)})
+	}
+	if budget.DaysTracked > 0 {
+		budget.AvgDailySpend = total / float64(budget.DaysTracked)
+	}
+
+	// Earlier months are budgeted for the days seen, since the period rarely
+	// starts on the 1st; this month gets its whole budget
+	for i := range budget.Months {
+		m := &budget.Months[i]
+		m.Budget = budget.BudgetFor(m.Month)
+		if i < len(budget.Months)-1 {
+			m.Budget *= float64(m.Days) / float64(m.DaysInMonth)
+		}
+		if budget.CarryOver && i > 0 {
+			m.CarriedIn = budget.Months[i-1].Remaining()
+		}
+	}
+
+	// Project the rest of this month at its pace so far, or the period's when
+	// there are no readings for it yet. Days already gone whose readings
+	// haven't arrived are costed at the same pace, and only today onwards is
+	// left to spend.
+	current := budget.CurrentMonth()
+	budget.MonthPace = budget.AvgDailySpend
+	if current.Days > 0 {
+		budget.MonthPace = current.Spent / float64(current.Days)
+	}
+	elapsed := today.In(london).Day() - 1
+	unread := max(0, elapsed-current.Days)
+	budget.RemainingDays = current.DaysInMonth - elapsed
+	budget.ProjectedMonthSpend = current.Spent + budget.MonthPace*float64(unread+budget.RemainingDays)
+	budget.ProjectedOvershoot = budget.ProjectedMonthSpend - current.Available()
+	budget.RemainingDailyAllowance = (current.Remaining() - budget.MonthPace*float64(unread)) / float64(budget.RemainingDays)
+
+	a.logger.Info("Tracked spend against budget",
+		"days", budget.DaysTracked,
+		"days_over", budget.DaysOverBudget,
+		"projected_month_spend", budget.ProjectedMonthSpend,
+		"projected_overshoot", budget.ProjectedOvershoot,
+	)
+
+	return budget
+}
+
+// dailyNetCosts returns each day's net cost in pounds (import and gas plus
+// standing charges, less export earnings), keyed by date. Only days with
+// complete readings for every fuel that has any are included.
+func dailyNetCosts(data *CollectedData) map[string]float64 {
+	fuels := [][]Consumption{data.ElectricityConsumption, data.GasConsumption}
+
+	var complete map[string]bool
+	for _, consumptions := range fuels {
+		if len(consumptions) == 0 {
+			continue
+		}
+		days := make(map[string]bool)
+		for _, day := range completeDays(consumptions) {
+			date := londonDate(day.StartAt)
+			if complete == nil || complete[date] {
+				days[date] = true
+			}
+		}
+		complete = days
+	}
+
+	costs := make(map[string]float64)
+	for date := range complete {
+		costs[date] = 0
+	}
+	add := func(consumptions []Consumption, sign float64) {
+		for _, c := range consumptions {
+			if date := londonDate(c.StartAt); complete[date] {
+				costs[date] += sign * c.Cost / 100
+			}
+		}
+	}
+	add(data.ElectricityConsumption, 1)
+	add(data.GasConsumption, 1)
+	add(data.ElectricityExport, -1)
+
+	for date, charge := range aggregateStandingChargesByDay(data.ElectricityStandingCharges, data.GasStandingCharges) {
+		if complete[date] {
+			costs[date] += charge / 100
+		}
+	}
+	return costs
+}
+
+// budgetInsights warns when spending is running ahead of the budget. Both
+// warnings are high priority; staying within budget gets a low-priority note.
+func budgetInsights(budget *BudgetTracking) []Insight {
+	if budget == nil {
+		return nil
+	}
+
+	current := budget.CurrentMonth()
+	month := current.Month.Format("January")
+	target := budget.TargetOn(current.Month)
+
+	if budget.ProjectedOvershoot > 0 {
+		action := fmt.Sprintf("Keep spending under %s a day for the remaining %d days to stay within budget", FormatCurrency(budget.RemainingDailyAllowance), budget.RemainingDays)
+		if budget.RemainingDailyAllowance <= 0 {
+			action = fmt.Sprintf("This month's budget is already spent - cut back where you can and consider whether %s a month is realistic", FormatCurrency(current.Available()))
+		}
+		return []Insight{{
+			Category: "budget",
+			Priority: "high",
+			Title:    fmt.Sprintf("On Course to Overspend %s's Budget", month),
+			Description: fmt.Sprintf("You've spent %s of your %s budget over %d days (%s a day against a target of %s). At this pace %s will cost %s, %s over budget.",
+				FormatCurrency(current.Spent),
+				FormatCurrency(current.Available()),
+				current.Days,
+				FormatCurrency(budget.MonthPace),
+				FormatCurrency(target),
+				month,
+				FormatCurrency(budget.ProjectedMonthSpend),
+				FormatCurrency(budget.ProjectedOvershoot),
+			),
+			Action: action,
+		}}
+	}
+
+	// A pace over target can still fit the month's budget, either thanks to
+	// credit carried over or because the monthly budget is the looser of the two
+	if budget.MonthPace > target {
+		reason := fmt.Sprintf("Only the %s carried over from earlier months keeps it within budget.", FormatCurrency(current.CarriedIn))
+		action := fmt.Sprintf("Bring daily spend back under %s so the carried-over credit isn't used up", FormatCurrency(target))
+		if !budget.CarryOver || current.CarriedIn <= 0 {
+			reason = fmt.Sprintf("It still fits the %s monthly budget, which is looser than the daily target.", FormatCurrency(current.Available()))
+			action = fmt.Sprintf("Bring daily spend back under %s, or drop the daily target if the monthly budget is the one that matters", FormatCurrency(target))
+		}
+		return []Insight{{
+			Category: "budget",
+			Priority: "high",
+			Title:    "Spending Above Your Daily Target",
+			Description: fmt.Sprintf("%s is averaging %s a day against a target of %s, with %d of %d days over. %s",
+				month,
+				FormatCurrency(budget.MonthPace),
+				FormatCurrency(target),
+				current.DaysOver,
+				current.Days,
+				reason,
+			),
+			Action: action,
+		}}
+	}
+
+	return []Insight{{
+		Category: "budget",
+		Priority: "low",
+		Title:    fmt.Sprintf("On Track for %s's Budget", month),
+		Description: fmt.Sprintf("At %s a day, %s is projected to cost %s against a budget of %s.",
+			FormatCurrency(budget.MonthPace),
+			month,
+			FormatCurrency(budget.ProjectedMonthSpend),
+			FormatCurrency(current.Available()),
+		),
+		Action: "No action needed - keep it up",
+	}}
+}
+
+// monthStart returns midnight on the first of a day's month, UK time
+func monthStart(day time.Time) time.Time {
+	local := day.In(london)
+	return time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, london)
+}
+
+// daysInMonth returns the number of days in a day's month, UK time
+func daysInMonth(day time.Time) int {
+	local := day.In(london)
+	return time.Date(local.Year(), local.Month()+1, 0, 0, 0, 0, 0, london).Day()
+}
@@ -103,8 +103,9 @@ func (cg *ChartGenerator) GenerateDailyUsageChart(data *CollectedData) (string,
 	return base64.StdEncoding.EncodeToString(buf), nil
 }
 
-// GenerateDailyCostChart creates a line chart showing daily costs
-func (cg *ChartGenerator) GenerateDailyCostChart(data *CollectedData) (string, error) {
+// GenerateDailyCostChart creates a line chart showing daily costs, with the
+// daily budget target when one is configured
+func (cg *ChartGenerator) GenerateDailyCostChart(data *CollectedData, budget *BudgetTracking) (string, error) {
 	if len(data.ElectricityConsumption) == 0 && len(data.GasConsumption) == 0 {
 		return "", fmt.Errorf("no consumption data available")
 	}
@@ -113,6 +114,7 @@ func (cg *ChartGenerator) GenerateDailyCostChart(data *CollectedData) (string, e
 	dailyElectricityCost := aggregateCostByDay(data.ElectricityConsumption)
 	dailyGasCost := aggregateCostByDay(data.GasConsumption)
 	dailyElectricityExportEarnings := aggregateCostByDay(data.ElectricityExport)
+	dailyStandingCharges := aggregateStandingChargesByDay(data.ElectricityStandingCharges, data.GasStandingCharges)
 
 	// Get all unique dates and sort them
 	dates := getUniqueSortedDates(dailyElectricityCost, dailyGasCost, dailyElectricityExportEarnings)
@@ -124,7 +126,9 @@ func (cg *ChartGenerator) GenerateDailyCostChart(data *CollectedData) (string, e
 	var electricityValues []float64
 	var gasValues []float64
 	var exportValues []float64
+	var standingValues []float64
 	var netValues []float64
+	var targetValues []float64
 	var labels []string
 
 	for _, date := range dates {
@@ -132,12 +136,17 @@ func (cg *ChartGenerator) GenerateDailyCostChart(data *CollectedData) (string, e
 		elecCost := dailyElectricityCost[date] / 100.0 // pence to pounds
 		gasCost := dailyGasCost[date] / 100.0
 		exportEarnings := dailyElectricityExportEarnings[date] / 100.0
-		netCost := elecCost + gasCost - exportEarnings
+		standingCharge := dailyStandingCharges[londonDate(date)] / 100.0
+		netCost := elecCost + gasCost + standingCharge - exportEarnings
 
 		electricityValues = append(electricityValues, elecCost)
 		gasValues = append(gasValues, gasCost)
 		exportValues = append(exportValues, exportEarnings)
+		standingValues = append(standingValues, standingCharge)
 		netValues = append(netValues, netCost)
+		if budget != nil {
+			targetValues = append(targetValues, budget.TargetOn(date))
+		}
 	}
 
 	// Prepare chart values
@@ -160,6 +169,14 @@ func (cg *ChartGenerator) GenerateDailyCostChart(data *CollectedData) (string, e
 		values = append(values, exportValues)
 		legendLabels = append(legendLabels, "Export Earnings (£)")
 	}
+	if len(dailyStandingCharges) > 0 {
+		values = append(values, standingValues)
+		legendLabels = append(legendLabels, "Standing Charges (£)")
+	}
+	if budget != nil {
+		values = append(values, targetValues)
+		legendLabels = append(legendLabels, "Daily Target (£)")
+	}
 
 	// Create the chart
 	p, err := charts.LineRender(
@@ -190,22 +207,191 @@ func (cg *ChartGenerator) GenerateDailyCostChart(data *CollectedData) (string, e
 	return base64.StdEncoding.EncodeToString(buf), nil
 }
 
-// aggregateByDay groups consumption values by date and sums them
+// GenerateLoadProfileChart creates a line chart of average usage through the
+// day, comparing weekdays with weekends
+func (cg *ChartGenerator) GenerateLoadProfileChart(profile *LoadProfile) (string, error) {
+	if profile == nil || len(profile.Weekday) == 0 {
+		return "", fmt.Errorf("no load profile available")
+	}
+
+	labels := make([]string, len(profile.Weekday))
+	for slot := range labels {
+		labels[slot] = slotLabel(slot)
+	}
+
+	// Create the chart
+	p, err := charts.LineRender(
+		[][]float64{profile.Weekday, profile.Weekend},
+		charts.TitleTextOptionFunc("Average Electricity Use by Time of Day"),
+		charts.XAxisDataOptionFunc(labels),
+		charts.LegendLabelsOptionFunc([]string{"Weekday (kWh)", "Weekend (kWh)"}, charts.PositionRight),
+		charts.ThemeOptionFunc(cg.getTheme()),
+		charts.WidthOptionFunc(1200),
+		charts.HeightOptionFunc(400),
+		charts.PaddingOptionFunc(charts.Box{
+			Top:    20,
+			Right:  20,
+			Bottom: 20,
+			Left:   20,
+		}),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to render load profile chart: %w", err)
+	}
+
+	// Convert to base64 for embedding in HTML
+	buf, err := p.Bytes()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate chart bytes: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// GenerateBalanceForecastChart creates a line chart of the projected account
+// balance at each month end on the current and recommended Direct Debits
+func (cg *ChartGenerator) GenerateBalanceForecastChart(forecast *BalanceForecast) (string, error) {
+	if forecast == nil || len(forecast.Months) == 0 {
+		return "", fmt.Errorf("no balance forecast available")
+	}
+
+	labels := make([]string, len(forecast.Months))
+	current := make([]float64, len(forecast.Months))
+	recommended := make([]float64, len(forecast.Months))
+	target := make([]float64, len(forecast.Months))
+	for i, month := range forecast.Months {
+		labels[i] = month.Month.Format("Jan 06")
+		current[i] = month.BalanceCurrent
+		recommended[i] = month.BalanceRecommended
+		target[i] = forecast.TargetBalance
+	}
+
+	// Create the chart
+	p, err := charts.LineRender(
+		[][]float64{current, recommended, target},
+		charts.TitleTextOptionFunc("Projected Account Balance"),
+		charts.XAxisDataOptionFunc(labels),
+		charts.LegendLabelsOptionFunc([]string{"Current Direct Debit (£)", "Recommended Direct Debit (£)", "Target (£)"}, charts.PositionRight),
+		charts.ThemeOptionFunc(cg.getTheme()),
+		charts.WidthOptionFunc(1200),
+		charts.HeightOptionFunc(400),
+		charts.PaddingOptionFunc(charts.Box{
+			Top:    20,
+			Right:  20,
+			Bottom: 20,
+			Left:   20,
+		}),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to render balance forecast chart: %w", err)
+	}
+
+	// Convert to base64 for embedding in HTML
+	buf, err := p.Bytes()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate chart bytes: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// GenerateTrendUsageChart creates a line chart of average daily usage in
+// each stored analysis
+func (cg *ChartGenerator) GenerateTrendUsageChart(history []TrendSnapshot) (string, error) {
+	return cg.renderTrendChart(history, "Average Daily Usage by Report", trendUsage, "kWh")
+}
+
+// GenerateTrendCostChart creates a line chart of average daily cost and export
+// earnings in each stored analysis
+func (cg *ChartGenerator) GenerateTrendCostChart(history []TrendSnapshot) (string, error) {
+	return cg.renderTrendChart(history, "Average Daily Cost by Report", trendCost, "£")
+}
+
+// renderTrendChart draws one line per metric of a kind across analyses,
+// leaving out metrics that were always zero
+func (cg *ChartGenerator) renderTrendChart(history []TrendSnapshot, title string, kind trendKind, unit string) (string, error) {
+	if len(history) == 0 {
+		return "", fmt.Errorf("no analysis history available")
+	}
+
+	labels := make([]string, len(history))
+	for i, snapshot := range history {
+		labels[i] = snapshot.GeneratedAt.In(london).Format("02 Jan 06")
+	}
+
+	var series [][]float64
+	var names []string
+	for _, metric := range trendMetrics {
+		if metric.kind != kind {
+			continue
+		}
+		values := metricValues(metric, history)
+		for _, value := range values {
+			if value != 0 {
+				series = append(series, values)
+				names = append(names, fmt.Sprintf("%s (%s)", metric.label, unit))
+				break
+			}
+		}
+	}
+	if len(series) == 0 {
+		return "", fmt.Errorf("no values to chart")
+	}
+
+	// Create the chart
+	p, err := charts.LineRender(
+		series,
+		charts.TitleTextOptionFunc(title),
+		charts.XAxisDataOptionFunc(labels),
+		charts.LegendLabelsOptionFunc(names, charts.PositionRight),
+		charts.ThemeOptionFunc(cg.getTheme()),
+		charts.WidthOptionFunc(1200),
+		charts.HeightOptionFunc(400),
+		charts.PaddingOptionFunc(charts.Box{
+			Top:    20,
+			Right:  20,
+			Bottom: 20,
+			Left:   20,
+		}),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to render trend chart: %w", err)
+	}
+
+	// Convert to base64 for embedding in HTML
+	buf, err := p.Bytes()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate chart bytes: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// aggregateByDay groups consumption values by UK calendar day and sums them
 func aggregateByDay(consumption []Consumption) map[time.Time]float64 {
 	daily := make(map[time.Time]float64)
 	for _, c := range consumption {
-		date := time.Date(c.StartAt.Year(), c.StartAt.Month(), c.StartAt.Day(), 0, 0, 0, 0, c.StartAt.Location())
-		daily[date] += c.Value
+		daily[londonDayStart(c.StartAt)] += c.Value
 	}
 	return daily
 }
 
-// aggregateCostByDay groups consumption costs by date and sums them
+// aggregateCostByDay groups consumption costs by UK calendar day and sums them
 func aggregateCostByDay(consumption []Consumption) map[time.Time]float64 {
 	daily := make(map[time.Time]float64)
 	for _, c := range consumption {
-		date := time.Date(c.StartAt.Year(), c.StartAt.Month(), c.StartAt.Day(), 0, 0, 0, 0, c.StartAt.Location())
-		daily[date] += c.Cost
+		daily[londonDayStart(c.StartAt)] += c.Cost
+	}
+	return daily
+}
+
+// aggregateStandingChargesByDay sums standing charges across fuels, keyed by date (YYYY-MM-DD)
+func aggregateStandingChargesByDay(chargeSets ...[]DailyStandingCharge) map[string]float64 {
+	daily := make(map[string]float64)
+	for _, charges := range chargeSets {
+		for _, c := range charges {
+			daily[londonDate(c.Date)] += c.Charge
+		}
 	}
 	return daily
 }
@@ -16,10 +16,15 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -28,7 +33,10 @@ import (
 type OctopusClient struct {
 	accountID  string
 	apiKey     string
+	graphqlURL string
+	restBase   string
 	httpClient *http.Client
+	retry      *RetryPolicy
 	logger     *Logger
 
 	// JWT token management
@@ -42,19 +50,24 @@ type OctopusClient struct {
 }
 
 // NewOctopusClient creates a new Octopus Energy API client
-func NewOctopusClient(accountID, apiKey string, logger *Logger) *OctopusClient {
+// A nil transport uses the default HTTP transport
+func NewOctopusClient(config *Config, transport http.RoundTripper, logger *Logger) *OctopusClient {
 	return &OctopusClient{
-		accountID: accountID,
-		apiKey:    apiKey,
+		accountID:  config.AccountID,
+		apiKey:     config.APIKey,
+		graphqlURL: config.GraphQLEndpoint,
+		restBase:   config.RESTAPIBase,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: transport,
 		},
+		retry:  NewRetryPolicy(config.RetryAttempts, logger),
 		logger: logger,
 	}
 }
 
 // ensureValidToken ensures we have a valid JWT token
-func (c *OctopusClient) ensureValidToken() error {
+func (c *OctopusClient) ensureValidToken(ctx context.Context) error {
 	c.jwtMutex.RLock()
 	hasValidToken := c.jwtToken != "" && time.Now().Before(c.jwtExpiry)
 	c.jwtMutex.RUnlock()
@@ -63,14 +76,19 @@ func (c *OctopusClient) ensureValidToken() error {
 		return nil
 	}
 
-	return c.refreshJWTToken()
+	return c.refreshJWTToken(ctx)
 }
 
 // refreshJWTToken obtains a new JWT token from the API
-func (c *OctopusClient) refreshJWTToken() error {
+func (c *OctopusClient) refreshJWTToken(ctx context.Context) error {
 	c.jwtMutex.Lock()
 	defer c.jwtMutex.Unlock()
 
+	// Another request may have refreshed the token while we waited for the lock
+	if c.jwtToken != "" && time.Now().Before(c.jwtExpiry) {
+		return nil
+	}
+
 	c.logger.Debug("Refreshing JWT token")
 
 	variables := map[string]interface{}{
@@ -87,7 +105,7 @@ func (c *OctopusClient) refreshJWTToken() error {
 		return fmt.Errorf("failed to marshal token request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", OctopusGraphQLEndpoint, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.graphqlURL, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to create token request: %w", err)
 	}
@@ -98,7 +116,7 @@ func (c *OctopusClient) refreshJWTToken() error {
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return &APIError{
-			Endpoint: OctopusGraphQLEndpoint,
+			Endpoint: c.graphqlURL,
 			Message:  "failed to request JWT token",
 			Err:      err,
 		}
@@ -109,8 +127,9 @@ func (c *OctopusClient) refreshJWTToken() error {
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		return &APIError{
 			StatusCode: resp.StatusCode,
-			Endpoint:   OctopusGraphQLEndpoint,
+			Endpoint:   c.graphqlURL,
 			Message:    fmt.Sprintf("token request failed: %s", string(bodyBytes)),
+			RetryAfter: parseRetryAfter(resp.Header),
 		}
 	}
 
@@ -139,10 +158,32 @@ func (c *OctopusClient) refreshJWTToken() error {
 	return nil
 }
 
-// makeGraphQLRequest makes a GraphQL request with proper authentication
-func (c *OctopusClient) makeGraphQLRequest(query string, variables map[string]interface{}, result interface{}) error {
+// makeGraphQLRequest makes a GraphQL request with proper authentication, retrying
+// transient failures and refreshing the JWT token once if it is rejected
+func (c *OctopusClient) makeGraphQLRequest(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
+	refreshed := false
+
+	return c.retry.Do(ctx, "graphql", func() error {
+		err := c.doGraphQLRequest(ctx, query, variables, result)
+
+		var authErr *AuthError
+		if errors.As(err, &authErr) && !refreshed {
+			refreshed = true
+			c.logger.Debug("JWT token rejected, refreshing and retrying", "error", err)
+			if err := c.refreshJWTToken(ctx); err != nil {
+				return err
+			}
+			return c.doGraphQLRequest(ctx, query, variables, result)
+		}
+
+		return err
+	})
+}
+
+// doGraphQLRequest performs a single GraphQL request attempt
+func (c *OctopusClient) doGraphQLRequest(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
 	// Ensure we have a valid token
-	if err := c.ensureValidToken(); err != nil {
+	if err := c.ensureValidToken(ctx); err != nil {
 		return err
 	}
 
@@ -164,7 +205,7 @@ func (c *OctopusClient) makeGraphQLRequest(query string, variables map[string]in
 		return fmt.Errorf("failed to marshal GraphQL request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", OctopusGraphQLEndpoint, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.graphqlURL, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to create GraphQL request: %w", err)
 	}
@@ -177,12 +218,12 @@ func (c *OctopusClient) makeGraphQLRequest(query string, variables map[string]in
 	req.Header.Set("Authorization", token)
 	req.Header.Set("User-Agent", GetUserAgent())
 
-	c.logger.LogAPIRequest("POST", OctopusGraphQLEndpoint)
+	c.logger.LogAPIRequest("POST", c.graphqlURL)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return &APIError{
-			Endpoint: OctopusGraphQLEndpoint,
+			Endpoint: c.graphqlURL,
 			Message:  "GraphQL request failed",
 			Err:      err,
 		}
@@ -197,7 +238,7 @@ func (c *OctopusClient) makeGraphQLRequest(query string, variables map[string]in
 
 	// Check for auth errors
 	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
-		// Invalidate token and retry once
+		// Invalidate token so the caller can refresh it and retry once
 		c.jwtMutex.Lock()
 		c.jwtToken = ""
 		c.jwtMutex.Unlock()
@@ -208,11 +249,12 @@ func (c *OctopusClient) makeGraphQLRequest(query string, variables map[string]in
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		c.logger.LogAPIError(OctopusGraphQLEndpoint, resp.StatusCode, fmt.Errorf("%s", string(bodyBytes)))
+		c.logger.LogAPIError(c.graphqlURL, resp.StatusCode, fmt.Errorf("%s", string(bodyBytes)))
 		return &APIError{
 			StatusCode: resp.StatusCode,
-			Endpoint:   OctopusGraphQLEndpoint,
+			Endpoint:   c.graphqlURL,
 			Message:    string(bodyBytes),
+			RetryAfter: parseRetryAfter(resp.Header),
 		}
 	}
 
@@ -225,7 +267,7 @@ func (c *OctopusClient) makeGraphQLRequest(query string, variables map[string]in
 }
 
 // FetchAccountDetails fetches account details including meter points and tariffs
-func (c *OctopusClient) FetchAccountDetails() (*Account, error) {
+func (c *OctopusClient) FetchAccountDetails(ctx context.Context) (*Account, error) {
 	c.logger.Info("Fetching account details", "account", c.accountID)
 
 	variables := map[string]interface{}{
@@ -233,7 +275,7 @@ func (c *OctopusClient) FetchAccountDetails() (*Account, error) {
 	}
 
 	var response AccountDetailsResponse
-	if err := c.makeGraphQLRequest(accountDetailsQuery, variables, &response); err != nil {
+	if err := c.makeGraphQLRequest(ctx, accountDetailsQuery, variables, &response); err != nil {
 		return nil, fmt.Errorf("failed to fetch account details: %w", err)
 	}
 
@@ -289,6 +331,7 @@ func (c *OctopusClient) FetchAccountDetails() (*Account, error) {
 					Tariff: Tariff{
 						DisplayName:    a.Tariff.DisplayName,
 						FullName:       a.Tariff.FullName,
+						TariffCode:     a.Tariff.TariffCode,
 						StandingCharge: a.Tariff.StandingCharge,
 						UnitRate:       a.Tariff.UnitRate,
 						DayRate:        a.Tariff.DayRate,
@@ -314,8 +357,9 @@ func (c *OctopusClient) FetchAccountDetails() (*Account, error) {
 					smartDevices[i] = SmartDevice{DeviceID: sd.DeviceID}
 				}
 				meterPoint.Meters[k] = Meter{
-					SerialNumber: m.SerialNumber,
-					SmartDevices: smartDevices,
+					SerialNumber:     m.SerialNumber,
+					ConsumptionUnits: m.ConsumptionUnits,
+					SmartDevices:     smartDevices,
 				}
 			}
 
@@ -333,6 +377,7 @@ func (c *OctopusClient) FetchAccountDetails() (*Account, error) {
 					Tariff: Tariff{
 						DisplayName:    a.Tariff.DisplayName,
 						FullName:       a.Tariff.FullName,
+						TariffCode:     a.Tariff.TariffCode,
 						StandingCharge: a.Tariff.StandingCharge,
 						UnitRate:       a.Tariff.UnitRate,
 						DayRate:        a.Tariff.DayRate,
@@ -350,3 +395,210 @@ func (c *OctopusClient) FetchAccountDetails() (*Account, error) {
 	c.logger.Info("Account details fetched successfully")
 	return account, nil
 }
+
+// FetchStatements fetches the account's most recent statements, newest first
+func (c *OctopusClient) FetchStatements(ctx context.Context, limit int) ([]Statement, error) {
+	c.logger.Info("Fetching statements", "account", c.accountID, "limit", limit)
+
+	variables := map[string]interface{}{
+		"accountNumber": c.accountID,
+		"first":         limit,
+	}
+
+	var response AccountBillsResponse
+	if err := c.makeGraphQLRequest(ctx, accountBillsQuery, variables, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch statements: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+	}
+
+	var statements []Statement
+	for _, edge := range response.Data.Account.Bills.Edges {
+		bill := edge.Node
+		// Other bill types (e.g. annual statements) carry no ledger totals
+		if bill.BillType != "" && bill.BillType != "STATEMENT" {
+			continue
+		}
+
+		issued, _ := time.Parse("2006-01-02", bill.IssuedDate)
+		from, _ := time.Parse("2006-01-02", bill.FromDate)
+		to, _ := time.Parse("2006-01-02", bill.ToDate)
+
+		statements = append(statements, Statement{
+			ID:                 bill.ID,
+			IssuedDate:         issued,
+			FromDate:           from,
+			ToDate:             to,
+			TotalAmount:        float64(bill.TotalCharges.GrossTotal - bill.TotalCredits.GrossTotal),
+			ChargeAmount:       float64(bill.TotalCharges.GrossTotal),
+			PaymentAmount:      float64(bill.TotalCredits.GrossTotal),
+			OutstandingBalance: float64(bill.ClosingBalance),
+		})
+	}
+
+	sort.Slice(statements, func(i, j int) bool {
+		return statements[i].IssuedDate.After(statements[j].IssuedDate)
+	})
+
+	c.logger.Info("Statements fetched successfully", "count", len(statements))
+	return statements, nil
+}
+
+// FetchPayments fetches payments from the account's most recent ledger
+// transactions, newest first
+func (c *OctopusClient) FetchPayments(ctx context.Context, limit int) ([]Payment, error) {
+	c.logger.Info("Fetching payments", "account", c.accountID, "limit", limit)
+
+	variables := map[string]interface{}{
+		"accountNumber": c.accountID,
+		"first":         limit,
+	}
+
+	var response AccountTransactionsResponse
+	if err := c.makeGraphQLRequest(ctx, accountTransactionsQuery, variables, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+	}
+
+	var payments []Payment
+	for _, edge := range response.Data.Account.Transactions.Edges {
+		transaction := edge.Node
+		if transaction.Typename != "Payment" {
+			continue
+		}
+
+		posted, _ := time.Parse("2006-01-02", transaction.PostedDate)
+		amount := transaction.Amounts.Gross
+		if amount < 0 {
+			amount = -amount
+		}
+
+		payments = append(payments, Payment{
+			ID:          transaction.ID,
+			Amount:      float64(amount),
+			PaymentDate: posted,
+			Method:      transaction.Title,
+		})
+	}
+
+	sort.Slice(payments, func(i, j int) bool {
+		return payments[i].PaymentDate.After(payments[j].PaymentDate)
+	})
+
+	c.logger.Info("Payments fetched successfully", "count", len(payments))
+	return payments, nil
+}
+
+// FetchPaymentSchedule fetches the account's payment schedule active today,
+// returning nil when the account has no active schedule
+func (c *OctopusClient) FetchPaymentSchedule(ctx context.Context) (*PaymentSchedule, error) {
+	c.logger.Info("Fetching payment schedule", "account", c.accountID)
+
+	variables := map[string]interface{}{
+		"accountNumber": c.accountID,
+	}
+
+	var response PaymentSchedulesResponse
+	if err := c.makeGraphQLRequest(ctx, paymentSchedulesQuery, variables, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch payment schedule: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+	}
+
+	today := now()
+	var active *PaymentSchedule
+	for _, edge := range response.Data.Account.PaymentSchedules.Edges {
+		node := edge.Node
+		validFrom, _ := time.Parse("2006-01-02", node.ValidFrom)
+		var validTo *time.Time
+		if node.ValidTo != nil {
+			t, _ := time.Parse("2006-01-02", *node.ValidTo)
+			validTo = &t
+		}
+
+		// Skip schedules that haven't started or have already ended
+		if validFrom.After(today) || (validTo != nil && validTo.Before(today)) {
+			continue
+		}
+		// Prefer the most recently started schedule
+		if active != nil && !validFrom.After(active.ValidFrom) {
+			continue
+		}
+
+		active = &PaymentSchedule{
+			ID:                  node.ID,
+			Amount:              float64(node.PaymentAmount) / 100.0, // Convert pence to pounds
+			Frequency:           node.PaymentFrequency,
+			FrequencyMultiplier: node.PaymentFrequencyMultiplier,
+			PaymentDay:          node.PaymentDay,
+			IsVariable:          node.IsVariablePaymentAmount,
+			ValidFrom:           validFrom,
+			ValidTo:             validTo,
+		}
+	}
+
+	if active == nil {
+		c.logger.Info("No active payment schedule found")
+		return nil, nil
+	}
+
+	active.NextPaymentDate = active.nextPaymentDate(today)
+
+	c.logger.Info("Payment schedule fetched successfully",
+		"amount", active.Amount,
+		"frequency", active.Frequency,
+		"payment_day", active.PaymentDay,
+	)
+	return active, nil
+}
+
+// FetchCompletedDispatches fetches the Intelligent Octopus smart-charge dispatches
+// completed recently (the API only keeps a short history)
+func (c *OctopusClient) FetchCompletedDispatches(ctx context.Context) ([]Dispatch, error) {
+	c.logger.Info("Fetching completed dispatches", "account", c.accountID)
+
+	variables := map[string]interface{}{
+		"accountNumber": c.accountID,
+	}
+
+	var response CompletedDispatchesResponse
+	if err := c.makeGraphQLRequest(ctx, completedDispatchesQuery, variables, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch completed dispatches: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+	}
+
+	dispatches := make([]Dispatch, 0, len(response.Data.CompletedDispatches))
+	for _, d := range response.Data.CompletedDispatches {
+		start, err := time.Parse(time.RFC3339, d.Start)
+		if err != nil {
+			c.logger.Debug("Skipping dispatch with invalid start", "start", d.Start)
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, d.End)
+		if err != nil {
+			c.logger.Debug("Skipping dispatch with invalid end", "end", d.End)
+			continue
+		}
+		delta, _ := strconv.ParseFloat(d.Delta, 64)
+
+		dispatches = append(dispatches, Dispatch{
+			Start:  start,
+			End:    end,
+			Delta:  math.Abs(delta),
+			Source: d.Meta.Source,
+		})
+	}
+
+	c.logger.Info("Completed dispatches fetched successfully", "count", len(dispatches))
+	return dispatches, nil
+}
@@ -15,15 +15,24 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// consumptionPageSize is the largest page the REST consumption endpoint accepts
+const consumptionPageSize = 25000
+
+// consumptionWindowDays bounds the span of a single consumption request so long
+// backfills are fetched as a series of smaller ranges
+const consumptionWindowDays = 90
+
 // FetchElectricityConsumption fetches electricity consumption data using REST API
-func (c *OctopusClient) FetchElectricityConsumption(mpan, serialNumber string, startDate, endDate time.Time) ([]Consumption, []Agreement, error) {
+func (c *OctopusClient) FetchElectricityConsumption(ctx context.Context, mpan, serialNumber string, startDate, endDate time.Time) ([]Consumption, []Agreement, error) {
 	c.logger.Info("Fetching electricity consumption",
 		"mpan", mpan,
 		"serial", serialNumber,
@@ -31,16 +40,8 @@ func (c *OctopusClient) FetchElectricityConsumption(mpan, serialNumber string, s
 		"end", endDate.Format("2006-01-02"),
 	)
 
-	// Build REST API URL with UTC timestamps
-	url := fmt.Sprintf("%s/electricity-meter-points/%s/meters/%s/consumption/?page_size=25000&period_from=%sZ&period_to=%sZ&order_by=period",
-		OctopusRESTAPIBase,
-		mpan,
-		serialNumber,
-		startDate.Format("2006-01-02T15:04:05"),
-		endDate.Format("2006-01-02T15:04:05"),
-	)
-
-	consumptions, err := c.fetchConsumptionREST(url, "electricity")
+	meterPath := fmt.Sprintf("electricity-meter-points/%s/meters/%s", mpan, serialNumber)
+	consumptions, err := c.fetchConsumptionWindowed(ctx, meterPath, "electricity", startDate, endDate)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -51,7 +52,7 @@ func (c *OctopusClient) FetchElectricityConsumption(mpan, serialNumber string, s
 }
 
 // FetchGasConsumption fetches gas consumption data using REST API
-func (c *OctopusClient) FetchGasConsumption(mprn, serialNumber string, startDate, endDate time.Time) ([]Consumption, []Agreement, error) {
+func (c *OctopusClient) FetchGasConsumption(ctx context.Context, mprn, serialNumber string, startDate, endDate time.Time) ([]Consumption, []Agreement, error) {
 	c.logger.Info("Fetching gas consumption",
 		"mprn", mprn,
 		"serial", serialNumber,
@@ -59,16 +60,8 @@ func (c *OctopusClient) FetchGasConsumption(mprn, serialNumber string, startDate
 		"end", endDate.Format("2006-01-02"),
 	)
 
-	// Build REST API URL with UTC timestamps
-	url := fmt.Sprintf("%s/gas-meter-points/%s/meters/%s/consumption/?page_size=25000&period_from=%sZ&period_to=%sZ&order_by=period",
-		OctopusRESTAPIBase,
-		mprn,
-		serialNumber,
-		startDate.Format("2006-01-02T15:04:05"),
-		endDate.Format("2006-01-02T15:04:05"),
-	)
-
-	consumptions, err := c.fetchConsumptionREST(url, "gas")
+	meterPath := fmt.Sprintf("gas-meter-points/%s/meters/%s", mprn, serialNumber)
+	consumptions, err := c.fetchConsumptionWindowed(ctx, meterPath, "gas", startDate, endDate)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -78,9 +71,114 @@ func (c *OctopusClient) FetchGasConsumption(mprn, serialNumber string, startDate
 	return consumptions, nil, nil
 }
 
-// fetchConsumptionREST is a helper method to fetch consumption data from REST API
-func (c *OctopusClient) fetchConsumptionREST(url, fuelType string) ([]Consumption, error) {
-	req, err := http.NewRequest("GET", url, nil)
+// fetchConsumptionWindowed splits the requested range into bounded windows and
+// fetches each one in turn, merging the results in period order
+func (c *OctopusClient) fetchConsumptionWindowed(ctx context.Context, meterPath, fuelType string, startDate, endDate time.Time) ([]Consumption, error) {
+	windows := splitDateRange(startDate, endDate, consumptionWindowDays)
+
+	var consumptions []Consumption
+	seen := make(map[int64]bool)
+
+	for i, window := range windows {
+		c.logger.Info("Fetching consumption window",
+			"fuel", fuelType,
+			"window", fmt.Sprintf("%d/%d", i+1, len(windows)),
+			"start", window[0].Format("2006-01-02"),
+			"end", window[1].Format("2006-01-02"),
+		)
+
+		// Build REST API URL with UTC timestamps
+		url := fmt.Sprintf("%s/%s/consumption/?page_size=%d&period_from=%sZ&period_to=%sZ&order_by=period",
+			c.restBase,
+			meterPath,
+			consumptionPageSize,
+			window[0].UTC().Format("2006-01-02T15:04:05"),
+			window[1].UTC().Format("2006-01-02T15:04:05"),
+		)
+
+		records, err := c.fetchConsumptionREST(ctx, url, fuelType)
+		if err != nil {
+			return nil, err
+		}
+
+		// Drop intervals repeated at window boundaries
+		for _, record := range records {
+			key := record.StartAt.Unix()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			consumptions = append(consumptions, record)
+		}
+	}
+
+	return consumptions, nil
+}
+
+// splitDateRange splits a date range into consecutive windows of at most maxDays
+func splitDateRange(startDate, endDate time.Time, maxDays int) [][2]time.Time {
+	if !endDate.After(startDate) {
+		return [][2]time.Time{{startDate, endDate}}
+	}
+
+	var windows [][2]time.Time
+	for from := startDate; from.Before(endDate); {
+		to := from.AddDate(0, 0, maxDays)
+		if to.After(endDate) {
+			to = endDate
+		}
+		windows = append(windows, [2]time.Time{from, to})
+		from = to
+	}
+
+	return windows
+}
+
+// fetchConsumptionREST fetches consumption data from the REST API, following
+// pagination links until every page has been read
+func (c *OctopusClient) fetchConsumptionREST(ctx context.Context, url, fuelType string) ([]Consumption, error) {
+	var consumptions []Consumption
+
+	for page := 1; url != ""; page++ {
+		var restResp *RESTConsumptionResponse
+		err := c.retry.Do(ctx, url, func() error {
+			var err error
+			restResp, err = c.fetchConsumptionPage(ctx, url, fuelType)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Convert to Consumption model
+		for _, r := range restResp.Results {
+			startAt, _ := time.Parse(time.RFC3339, r.IntervalStart)
+			endAt, _ := time.Parse(time.RFC3339, r.IntervalEnd)
+
+			consumptions = append(consumptions, Consumption{
+				StartAt: startAt,
+				EndAt:   endAt,
+				Value:   r.Consumption,
+				Cost:    0, // Cost not provided by REST API, will be calculated from tariff
+			})
+		}
+
+		c.logger.Debug("Fetched consumption page",
+			"fuel", fuelType,
+			"page", page,
+			"records", len(restResp.Results),
+			"total", restResp.Count,
+		)
+
+		url = restResp.Next
+	}
+
+	return consumptions, nil
+}
+
+// fetchConsumptionPage fetches and decodes a single page of consumption data
+func (c *OctopusClient) fetchConsumptionPage(ctx context.Context, url, fuelType string) (*RESTConsumptionResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -108,6 +206,7 @@ func (c *OctopusClient) fetchConsumptionREST(url, fuelType string) ([]Consumptio
 			StatusCode: resp.StatusCode,
 			Endpoint:   url,
 			Message:    string(bodyBytes),
+			RetryAfter: parseRetryAfter(resp.Header),
 		}
 	}
 
@@ -116,56 +215,52 @@ func (c *OctopusClient) fetchConsumptionREST(url, fuelType string) ([]Consumptio
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// Convert to Consumption model
-	consumptions := make([]Consumption, len(restResp.Results))
-	for i, r := range restResp.Results {
-		startAt, _ := time.Parse(time.RFC3339, r.IntervalStart)
-		endAt, _ := time.Parse(time.RFC3339, r.IntervalEnd)
-
-		consumptions[i] = Consumption{
-			StartAt: startAt,
-			EndAt:   endAt,
-			Value:   r.Consumption,
-			Cost:    0, // Cost not provided by REST API, will be calculated from tariff
-		}
-	}
-
-	return consumptions, nil
+	return &restResp, nil
 }
 
-// FetchProductCode fetches the product code from the Products API based on tariff display name
-func (c *OctopusClient) FetchProductCode(tariffDisplayName string) (string, error) {
-	url := fmt.Sprintf("%s/products/", OctopusRESTAPIBase)
+// FetchProductCode fetches the product code from the Products API based on tariff display name.
+// With matchPrefix, a name that isn't found exactly may match the start of a
+// versioned one; that's only safe for comparison candidates, never the billed tariff.
+func (c *OctopusClient) FetchProductCode(ctx context.Context, tariffDisplayName string, matchPrefix bool) (string, error) {
+	url := fmt.Sprintf("%s/products/", c.restBase)
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
+	var productsResp ProductsResponse
+	err := c.retry.Do(ctx, url, func() error {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
 
-	req.Header.Set("User-Agent", GetUserAgent())
+		req.Header.Set("User-Agent", GetUserAgent())
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", &APIError{
-			Endpoint: url,
-			Message:  "failed to fetch products",
-			Err:      err,
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return &APIError{
+				Endpoint: url,
+				Message:  "failed to fetch products",
+				Err:      err,
+			}
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			return &APIError{
+				StatusCode: resp.StatusCode,
+				Endpoint:   url,
+				Message:    string(bodyBytes),
+				RetryAfter: parseRetryAfter(resp.Header),
+			}
 		}
-	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return "", &APIError{
-			StatusCode: resp.StatusCode,
-			Endpoint:   url,
-			Message:    string(bodyBytes),
+		if err := json.NewDecoder(resp.Body).Decode(&productsResp); err != nil {
+			return fmt.Errorf("failed to decode products response: %w", err)
 		}
-	}
 
-	var productsResp ProductsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&productsResp); err != nil {
-		return "", fmt.Errorf("failed to decode products response: %w", err)
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	// Find matching product by display name
@@ -176,74 +271,155 @@ func (c *OctopusClient) FetchProductCode(tariffDisplayName string) (string, erro
 		}
 	}
 
+	if !matchPrefix {
+		return "", fmt.Errorf("product code not found for tariff: %s", tariffDisplayName)
+	}
+
+	// Fall back to a prefix match so versioned names (e.g. "Octopus 12M Fixed October 2025") resolve
+	var matches []string
+	code := ""
+	for _, product := range productsResp.Results {
+		if strings.HasPrefix(strings.ToLower(product.DisplayName), strings.ToLower(tariffDisplayName)) {
+			if code == "" {
+				code = product.Code
+			}
+			matches = append(matches, product.DisplayName)
+		}
+	}
+	if len(matches) > 1 {
+		c.logger.Warn("Several products match tariff name, using the first - set a product code to choose another",
+			"tariff", tariffDisplayName,
+			"matches", strings.Join(matches, ", "),
+			"code", code,
+		)
+	}
+	if code != "" {
+		c.logger.Info("Found product code by prefix", "tariff", tariffDisplayName, "product", matches[0], "code", code)
+		return code, nil
+	}
+
 	return "", fmt.Errorf("product code not found for tariff: %s", tariffDisplayName)
 }
 
-// FetchElectricityTariffRates fetches time-varying unit rates for an electricity tariff
-func (c *OctopusClient) FetchElectricityTariffRates(productCode string, startDate, endDate time.Time) ([]TariffRate, error) {
-	// Construct tariff code from product code (format: E-1R-{PRODUCT_CODE}-C for standard region)
-	tariffCode := fmt.Sprintf("E-1R-%s-C", productCode)
+// FetchElectricityTariffRates fetches time-varying unit rates for an electricity tariff in a GSP region
+func (c *OctopusClient) FetchElectricityTariffRates(ctx context.Context, productCode, region string, startDate, endDate time.Time) ([]TariffRate, error) {
+	return c.fetchProductRates(ctx, "electricity", productCode, region, "standard-unit-rates", startDate, endDate)
+}
+
+// FetchElectricityStandingCharges fetches the daily standing charges for an electricity tariff in a GSP region
+func (c *OctopusClient) FetchElectricityStandingCharges(ctx context.Context, productCode, region string, startDate, endDate time.Time) ([]TariffRate, error) {
+	return c.fetchProductRates(ctx, "electricity", productCode, region, "standing-charges", startDate, endDate)
+}
+
+// FetchGasTariffRates fetches time-varying unit rates for a gas tariff in a GSP region
+// (Tracker products change price daily)
+func (c *OctopusClient) FetchGasTariffRates(ctx context.Context, productCode, region string, startDate, endDate time.Time) ([]TariffRate, error) {
+	return c.fetchProductRates(ctx, "gas", productCode, region, "standard-unit-rates", startDate, endDate)
+}
+
+// FetchGasStandingCharges fetches the daily standing charges for a gas tariff in a GSP region
+func (c *OctopusClient) FetchGasStandingCharges(ctx context.Context, productCode, region string, startDate, endDate time.Time) ([]TariffRate, error) {
+	return c.fetchProductRates(ctx, "gas", productCode, region, "standing-charges", startDate, endDate)
+}
+
+// fetchProductRates fetches a rate listing (standard-unit-rates or standing-charges)
+// for a single-register tariff of the given fuel
+func (c *OctopusClient) fetchProductRates(ctx context.Context, fuelType, productCode, region, listing string, startDate, endDate time.Time) ([]TariffRate, error) {
+	// Construct tariff code from product code (format: {E|G}-1R-{PRODUCT_CODE}-{REGION})
+	prefix := "E"
+	if fuelType == "gas" {
+		prefix = "G"
+	}
+	tariffCode := fmt.Sprintf("%s-1R-%s-%s", prefix, productCode, region)
 
-	url := fmt.Sprintf("%s/products/%s/electricity-tariffs/%s/standard-unit-rates/?period_from=%sZ&period_to=%sZ",
-		OctopusRESTAPIBase,
+	url := fmt.Sprintf("%s/products/%s/%s-tariffs/%s/%s/?period_from=%sZ&period_to=%sZ",
+		c.restBase,
 		productCode,
+		fuelType,
 		tariffCode,
-		startDate.Format("2006-01-02T15:04:05"),
-		endDate.Format("2006-01-02T15:04:05"),
+		listing,
+		startDate.UTC().Format("2006-01-02T15:04:05"),
+		endDate.UTC().Format("2006-01-02T15:04:05"),
 	)
 
-	req, err := http.NewRequest("GET", url, nil)
+	description := strings.ReplaceAll(listing, "-", " ")
+	rates, err := c.fetchRates(ctx, url, description)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("User-Agent", GetUserAgent())
-
-	c.logger.LogAPIRequest("GET", url)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, &APIError{
-			Endpoint: url,
-			Message:  "failed to fetch tariff rates",
-			Err:      err,
-		}
-	}
-	defer resp.Body.Close()
+	c.logger.Info("Fetched "+fuelType+" "+description, "tariff", tariffCode, "count", len(rates))
+	return rates, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		c.logger.LogAPIError(url, resp.StatusCode, fmt.Errorf("%s", string(bodyBytes)))
-		return nil, &APIError{
-			StatusCode: resp.StatusCode,
-			Endpoint:   url,
-			Message:    string(bodyBytes),
+// fetchRates fetches a Products API rate listing (unit rates or standing charges),
+// following pagination links until every page has been read
+func (c *OctopusClient) fetchRates(ctx context.Context, url, description string) ([]TariffRate, error) {
+	var rates []TariffRate
+
+	for url != "" {
+		var ratesResp TariffRatesResponse
+		pageURL := url
+		err := c.retry.Do(ctx, pageURL, func() error {
+			req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+			if err != nil {
+				return fmt.Errorf("failed to create request: %w", err)
+			}
+
+			req.Header.Set("User-Agent", GetUserAgent())
+
+			c.logger.LogAPIRequest("GET", pageURL)
+
+			resp, err := c.httpClient.Do(req)
+			if err != nil {
+				return &APIError{
+					Endpoint: pageURL,
+					Message:  fmt.Sprintf("failed to fetch %s", description),
+					Err:      err,
+				}
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				c.logger.LogAPIError(pageURL, resp.StatusCode, fmt.Errorf("%s", string(bodyBytes)))
+				return &APIError{
+					StatusCode: resp.StatusCode,
+					Endpoint:   pageURL,
+					Message:    string(bodyBytes),
+					RetryAfter: parseRetryAfter(resp.Header),
+				}
+			}
+
+			if err := json.NewDecoder(resp.Body).Decode(&ratesResp); err != nil {
+				return fmt.Errorf("failed to decode %s response: %w", description, err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	var ratesResp TariffRatesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&ratesResp); err != nil {
-		return nil, fmt.Errorf("failed to decode rates response: %w", err)
-	}
-
-	// Convert to TariffRate model
-	rates := make([]TariffRate, len(ratesResp.Results))
-	for i, r := range ratesResp.Results {
-		validFrom, _ := time.Parse(time.RFC3339, r.ValidFrom)
-		var validTo *time.Time
-		if r.ValidTo != nil {
-			t, _ := time.Parse(time.RFC3339, *r.ValidTo)
-			validTo = &t
+		// Convert to TariffRate model
+		for _, r := range ratesResp.Results {
+			validFrom, _ := time.Parse(time.RFC3339, r.ValidFrom)
+			var validTo *time.Time
+			if r.ValidTo != nil {
+				t, _ := time.Parse(time.RFC3339, *r.ValidTo)
+				validTo = &t
+			}
+
+			rates = append(rates, TariffRate{
+				ValidFrom:   validFrom,
+				ValidTo:     validTo,
+				ValueExcVAT: r.ValueExcVAT,
+				ValueIncVAT: r.ValueIncVAT,
+			})
 		}
 
-		rates[i] = TariffRate{
-			ValidFrom:   validFrom,
-			ValidTo:     validTo,
-			ValueExcVAT: r.ValueExcVAT,
-			ValueIncVAT: r.ValueIncVAT,
-		}
+		url = ratesResp.Next
 	}
 
-	c.logger.Info("Fetched electricity tariff rates", "count", len(rates))
 	return rates, nil
 }
@@ -0,0 +1,49 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"time"
+	_ "time/tzdata" // Embed zone data so Europe/London resolves on every platform
+)
+
+// now returns the reference time used for analysis periods and report dates.
+// Replay mode pins it to the time the session was recorded so that request
+// URLs and report output match the original run exactly.
+var now = time.Now
+
+// london is the Europe/London time zone, used for UK tariff windows and calendar days
+var london = mustLoadLocation("Europe/London")
+
+// londonDayStart returns midnight at the start of t's calendar day in
+// Europe/London. Adding a day to it with AddDate gives the next midnight, so
+// clock-change days are 23 or 25 hours long.
+func londonDayStart(t time.Time) time.Time {
+	t = t.In(london)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, london)
+}
+
+// londonDate returns t's Europe/London calendar date as YYYY-MM-DD
+func londonDate(t time.Time) string {
+	return t.In(london).Format("2006-01-02")
+}
+
+// mustLoadLocation loads a time zone from the embedded zone database
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic("failed to load time zone " + name + ": " + err.Error())
+	}
+	return loc
+}
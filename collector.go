@@ -14,11 +14,30 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 )
 
+const (
+	// statementHistoryLimit is the number of recent bills requested
+	statementHistoryLimit = 12
+
+	// transactionHistoryLimit is the number of recent ledger transactions searched for payments
+	transactionHistoryLimit = 100
+
+	// dispatchCacheTTL keeps completed dispatches for as long as the longest analysis period,
+	// since the API forgets them after a few days
+	dispatchCacheTTL = (MaxAnalysisPeriodDays + 1) * 24 * time.Hour
+
+	// dispatchSourceBumpCharge marks dispatches the customer asked for, which
+	// are billed at the normal rate rather than off-peak
+	dispatchSourceBumpCharge = "bump-charge"
+)
+
 // Collector orchestrates data collection from the Octopus Energy API
 type Collector struct {
 	client  *OctopusClient
@@ -37,39 +56,55 @@ func NewCollector(client *OctopusClient, config *Config, storage *Storage, logge
 	}
 }
 
-// CollectAll fetches all required data from the API
-func (c *Collector) CollectAll() (*CollectedData, error) {
-	c.logger.Info("Starting data collection")
+// CollectAll fetches all required data from the API. Independent requests run
+// concurrently on a bounded worker pool in phases (account, then consumption and
+// rates for each property); each phase's results are merged in a fixed order once
+// it finishes, so the output and logs don't depend on which request returned
+// first. Accounts with several selected properties return an account-level
+// rollup with the per-property data in Properties.
+func (c *Collector) CollectAll(ctx context.Context) (*CollectedData, error) {
+	c.logger.Info("Starting data collection", "workers", c.config.CollectionWorkers)
 
-	data := &CollectedData{
-		FetchedAt: time.Now(),
-	}
+	fetchedAt := now()
 
-	// Fetch account details (try cache first - cache for 1 hour)
+	// Phase 1: account details and billing history
 	c.logger.Info("Fetching account details")
-	cacheKey := fmt.Sprintf("account_%s", c.config.AccountID)
-	var account *Account
-	cached, err := c.storage.LoadCache(cacheKey, &account)
-	if err != nil {
-		c.logger.Warn("Failed to load account from cache", "error", err)
+	var (
+		account      *Account
+		accountErr   error
+		statements   []Statement
+		statementErr error
+		payments     []Payment
+		paymentErr   error
+		schedule     *PaymentSchedule
+		scheduleErr  error
+	)
+	if err := c.runPhase(ctx, "account", []task{
+		func(ctx context.Context) { account, accountErr = c.fetchAccountCached(ctx) },
+		func(ctx context.Context) { statements, statementErr = c.fetchStatementsCached(ctx) },
+		func(ctx context.Context) { payments, paymentErr = c.fetchPaymentsCached(ctx) },
+		func(ctx context.Context) { schedule, scheduleErr = c.fetchPaymentScheduleCached(ctx) },
+	}); err != nil {
+		return nil, err
 	}
 
-	if !cached {
-		account, err = c.client.FetchAccountDetails()
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch account details: %w", err)
-		}
-		// Cache account details for 1 hour
-		if err := c.storage.SaveCache(cacheKey, account, 1*time.Hour); err != nil {
-			c.logger.Warn("Failed to cache account details", "error", err)
-		}
-	} else {
-		c.logger.Info("Loaded account details from cache")
+	if accountErr != nil {
+		return nil, fmt.Errorf("failed to fetch account details: %w", accountErr)
+	}
+
+	// Billing history is useful context but not required for analysis
+	if statementErr != nil {
+		c.logger.Warn("Failed to fetch statements", "error", statementErr)
+	}
+	if paymentErr != nil {
+		c.logger.Warn("Failed to fetch payments", "error", paymentErr)
+	}
+	if scheduleErr != nil {
+		c.logger.Warn("Failed to fetch payment schedule", "error", scheduleErr)
 	}
-	data.Account = account
 
 	// Calculate date range
-	endDate := time.Now()
+	endDate := now()
 	startDate := endDate.AddDate(0, 0, -c.config.AnalysisPeriodDays)
 
 	c.logger.Info("Analysis period",
@@ -78,204 +113,612 @@ func (c *Collector) CollectAll() (*CollectedData, error) {
 		"days", c.config.AnalysisPeriodDays,
 	)
 
-	// Auto-discover meters from account if not explicitly configured
-	exportMPAN, exportSerials, exportAgreements := c.discoverMeters(account)
-
-	// Fetch electricity consumption if configured or discovered
-	if c.config.ElectricityMPAN != "" && c.config.ElectricitySerial != "" {
-		c.logger.Info("Fetching electricity consumption data")
-		consumptions, _, err := c.client.FetchElectricityConsumption(
-			c.config.ElectricityMPAN,
-			c.config.ElectricitySerial,
-			startDate,
-			endDate,
-		)
+	properties, err := c.selectProperties(account)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each property is collected in turn; requests within a property run concurrently.
+	// Dispatches belong to the account rather than a meter, so they're fetched
+	// once, for the first property on Intelligent Octopus.
+	configured := c.configuredPropertyIndex(properties)
+	collected := make([]*CollectedData, 0, len(properties))
+	dispatchesClaimed := false
+	for i, property := range properties {
+		meters := c.discoverMeters(property, i == configured)
+		intelligent := meters.ElectricityMPAN != "" && meters.ElectricitySerial != "" &&
+			onIntelligentTariff(electricityAgreementsFor(property, meters.ElectricityMPAN))
+		if intelligent && dispatchesClaimed {
+			c.logger.Warn("Dispatches can't be matched to a property, so only the first Intelligent Octopus property is repriced",
+				"property", property.Label(),
+			)
+			intelligent = false
+		}
+		dispatchesClaimed = dispatchesClaimed || intelligent
+
+		propertyData, err := c.collectProperty(ctx, account, property, meters, intelligent, startDate, endDate)
 		if err != nil {
-			c.logger.Warn("Failed to fetch electricity consumption", "error", err)
-			// Continue with other data collection
-		} else {
-			// Get agreements from account details
-			var agreements []Agreement
-			for _, prop := range account.Properties {
-				for _, emp := range prop.ElectricityMeterPoints {
-					if emp.MPAN == c.config.ElectricityMPAN {
-						agreements = emp.Agreements
-						break
-					}
-				}
+			return nil, err
+		}
+		collected = append(collected, propertyData)
+	}
+
+	data := collected[0]
+	if len(collected) > 1 {
+		data = RollupCollectedData(collected)
+	}
+	data.Account = account
+	data.FetchedAt = fetchedAt
+	data.Statements = statements
+	data.Payments = payments
+	data.PaymentSchedule = schedule
+
+	// Log summary of collected data
+	c.logger.Info("Data collection completed successfully")
+	c.logger.Info("Collection summary",
+		"electricity_records", len(data.ElectricityConsumption),
+		"export_records", len(data.ElectricityExport),
+		"gas_records", len(data.GasConsumption),
+		"electricity_agreements", len(data.ElectricityAgreements),
+		"export_agreements", len(data.ElectricityExportAgreements),
+		"gas_agreements", len(data.GasAgreements),
+		"properties", len(collected),
+	)
+
+	return data, nil
+}
+
+// collectProperty fetches and prices consumption for one property's meters.
+// Independent requests run concurrently on the worker pool in two phases
+// (consumption, then rates), and the results are merged in a fixed order.
+func (c *Collector) collectProperty(ctx context.Context, account *Account, property Property, meters propertyMeters,
+	intelligent bool, startDate, endDate time.Time) (*CollectedData, error) {
+	c.logger.Info("Collecting property", "property", property.Label(), "id", property.ID)
+
+	data := &CollectedData{
+		Account:         account,
+		PropertyID:      property.ID,
+		PropertyAddress: property.Address,
+		FetchedAt:       now(),
+	}
+
+	haveElectricity := meters.ElectricityMPAN != "" && meters.ElectricitySerial != ""
+	haveExport := meters.ExportMPAN != "" && len(meters.ExportSerials) > 0
+	haveGas := meters.GasMPRN != "" && meters.GasSerial != ""
+
+	// Work out agreements, regions and pricing for each meter point up front,
+	// since the fetches below depend on them
+	var (
+		electricityAgreements []Agreement
+		electricityRegion     string
+		electricityRegionSrc  string
+		pricingModel          PricingModel
+		reportedModel         PricingModel
+	)
+	if haveElectricity {
+		// Get agreements from account details
+		electricityAgreements = electricityAgreementsFor(property, meters.ElectricityMPAN)
+
+		// Detect if user configured an export meter instead of import meter
+		if len(electricityAgreements) > 0 {
+			tariffName := electricityAgreements[0].Tariff.DisplayName
+			if containsIgnoreCase(tariffName, "export") {
+				c.logger.Warn("⚠️  CONFIGURATION WARNING: You have configured an EXPORT meter",
+					"mpan", meters.ElectricityMPAN,
+					"tariff", tariffName,
+				)
+				c.logger.Warn("Export meters track solar/battery exports to the grid, not consumption")
+				c.logger.Warn("Please update electricity_mpan in config.yaml to your IMPORT meter for consumption tracking")
+				c.logger.Warn("Check your account details for the meter with 'Import' or consumption tariff")
 			}
+		}
 
-			// Detect if user configured an export meter instead of import meter
-			if len(agreements) > 0 {
-				tariffName := agreements[0].Tariff.DisplayName
-				if containsIgnoreCase(tariffName, "export") {
-					c.logger.Warn("⚠️  CONFIGURATION WARNING: You have configured an EXPORT meter",
-						"mpan", c.config.ElectricityMPAN,
-						"tariff", tariffName,
-					)
-					c.logger.Warn("Export meters track solar/battery exports to the grid, not consumption")
-					c.logger.Warn("Please update electricity_mpan in config.yaml to your IMPORT meter for consumption tracking")
-					c.logger.Warn("Check your account details for the meter with 'Import' or consumption tariff")
-				}
+		// Work out which GSP region this meter point is priced in
+		electricityRegion, electricityRegionSrc = ResolveRegion(c.config.Region, meters.ElectricityMPAN, electricityAgreements)
+		c.logger.Info("Determined electricity region",
+			"region", electricityRegion,
+			"area", RegionName(electricityRegion),
+			"source", electricityRegionSrc,
+		)
+
+		// Work out which time-of-use windows apply when pricing from agreement rates
+		pricingModel, _ = c.config.PricingModelOverride()
+		reportedModel = pricingModel
+		if reportedModel == nil {
+			reportedModel = DetectPricingModel(currentTariff(electricityAgreements))
+		}
+		c.logger.Debug("Determined pricing model", "model", reportedModel.Name())
+	} else {
+		c.logger.Info("Skipping electricity consumption (not configured)")
+	}
+
+	var exportRegion string
+	if haveExport {
+		// Export meters share the import meter's region unless their own data says otherwise
+		var regionSource string
+		exportRegion, regionSource = ResolveRegion(c.config.Region, meters.ExportMPAN, meters.ExportAgreements)
+		if regionSource == RegionSourceDefault && electricityRegion != "" {
+			exportRegion, regionSource = electricityRegion, electricityRegionSrc
+		}
+		c.logger.Debug("Determined export region", "region", exportRegion, "source", regionSource)
+	}
+
+	var (
+		gasAgreements []Agreement
+		gasRegion     string
+	)
+	if haveGas {
+		// Get agreements from account details
+		for _, gmp := range property.GasMeterPoints {
+			if gmp.MPRN == meters.GasMPRN {
+				gasAgreements = gmp.Agreements
+				break
 			}
+		}
+
+		// Gas meter points carry no distributor ID, so fall back to the electricity region
+		var regionSource string
+		gasRegion, regionSource = ResolveRegion(c.config.Region, "", gasAgreements)
+		if regionSource == RegionSourceDefault && electricityRegion != "" {
+			gasRegion, regionSource = electricityRegion, electricityRegionSrc
+		}
+		c.logger.Debug("Determined gas region", "region", gasRegion, "source", regionSource)
+	} else {
+		c.logger.Info("Skipping gas consumption (not configured)")
+	}
+
+	// Each tariff in force during the period has its product code looked up
+	// once, however many meters share it
+	var tariffNames []string
+	for _, agreements := range [][]Agreement{electricityAgreements, meters.ExportAgreements, gasAgreements} {
+		for _, span := range agreementSpans(agreements, startDate, endDate) {
+			if !slices.Contains(tariffNames, span.Tariff.DisplayName) {
+				tariffNames = append(tariffNames, span.Tariff.DisplayName)
+			}
+		}
+	}
+	productCodes := make([]string, len(tariffNames))
+	productErrs := make([]error, len(tariffNames))
+	productCode := func(agreements []Agreement) (map[string]string, error) {
+		codes := make(map[string]string)
+		for _, span := range agreementSpans(agreements, startDate, endDate) {
+			i := slices.Index(tariffNames, span.Tariff.DisplayName)
+			if productErrs[i] != nil {
+				return nil, productErrs[i]
+			}
+			codes[span.Tariff.DisplayName] = productCodes[i]
+		}
+		return codes, nil
+	}
+
+	// Phase 2: consumption, product codes and smart-charge dispatches
+	var (
+		tasks           []task
+		electricity     = make([]serialReadings, len(meters.ElectricitySerials))
+		electricityErrs = make([]error, len(meters.ElectricitySerials))
+		exports         = make([]serialReadings, len(meters.ExportSerials))
+		exportErrs      = make([]error, len(meters.ExportSerials))
+		gas             = make([]serialReadings, len(meters.GasSerials))
+		gasErrs         = make([]error, len(meters.GasSerials))
+		dispatches      []Dispatch
+		dispatchErr     error
+	)
+	if haveElectricity {
+		c.logger.Info("Fetching electricity consumption data", "serials", len(meters.ElectricitySerials))
+		for i, serial := range meters.ElectricitySerials {
+			electricity[i].Serial = serial
+			tasks = append(tasks, func(ctx context.Context) {
+				electricity[i].Consumptions, _, electricityErrs[i] = c.client.FetchElectricityConsumption(ctx,
+					meters.ElectricityMPAN,
+					serial,
+					startDate,
+					endDate,
+				)
+			})
+		}
+		if intelligent {
+			tasks = append(tasks, func(ctx context.Context) {
+				dispatches, dispatchErr = c.collectDispatches(ctx, startDate, endDate)
+			})
+		}
+	}
+	if haveExport {
+		// Some export meters have several serials, so fetch them all and merge them
+		c.logger.Info("Fetching solar/battery export data", "serials", len(meters.ExportSerials))
+		for i, serial := range meters.ExportSerials {
+			exports[i].Serial = serial
+			tasks = append(tasks, func(ctx context.Context) {
+				exports[i].Consumptions, _, exportErrs[i] = c.client.FetchElectricityConsumption(ctx, meters.ExportMPAN, serial, startDate, endDate)
+			})
+		}
+	}
+	if haveGas {
+		c.logger.Info("Fetching gas consumption data", "serials", len(meters.GasSerials))
+		for i, serial := range meters.GasSerials {
+			gas[i].Serial = serial
+			tasks = append(tasks, func(ctx context.Context) {
+				gas[i].Consumptions, _, gasErrs[i] = c.client.FetchGasConsumption(ctx,
+					meters.GasMPRN,
+					serial,
+					startDate,
+					endDate,
+				)
+			})
+		}
+	}
+	for i, name := range tariffNames {
+		tasks = append(tasks, func(ctx context.Context) {
+			productCodes[i], productErrs[i] = c.fetchProductCodeCached(ctx, name, false)
+		})
+	}
+	if err := c.runPhase(ctx, "consumption", tasks); err != nil {
+		return nil, err
+	}
+
+	// Gas meters swapped between SMETS1 and SMETS2 report in different units,
+	// so each serial is converted to kWh before merging
+	if haveGas {
+		var err error
+		data.GasUnit, data.GasUnitSource, err = c.convertGasSerials(property, meters, gas)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Merge readings from every serial on each meter point
+	electricityReadings, electricityErr := c.stitchSerials("electricity", electricity, electricityErrs)
+	exportReadings, exportErr := c.stitchSerials("export", exports, exportErrs)
+	gasReadings, gasErr := c.stitchSerials("gas", gas, gasErrs)
+	exportConsumption := exportReadings.Consumptions
+	for _, stitched := range []StitchedReadings{electricityReadings, exportReadings, gasReadings} {
+		data.MeterSegments = append(data.MeterSegments, stitched.Segments...)
+		data.MeterExchanges = append(data.MeterExchanges, stitched.Exchanges...)
+	}
+
+	// Phase 3: Products API rates for each meter point that returned consumption
+	tasks = nil
+	var (
+		electricityRates       []TariffRate
+		electricityRatesErr    error
+		electricityStanding    []TariffRate
+		electricityStandingErr error
+		exportRates            []TariffRate
+		exportRatesErr         error
+		gasRates               []TariffRate
+		gasRatesErr            error
+		gasStanding            []TariffRate
+		gasStandingErr         error
+	)
+	if haveElectricity && electricityErr == nil && len(electricityAgreements) > 0 {
+		if codes, err := productCode(electricityAgreements); err == nil {
+			c.logger.Info("Fetching time-varying electricity rates from Products API")
+			tasks = append(tasks,
+				func(ctx context.Context) {
+					electricityRates, electricityRatesErr = c.fetchAgreementRates(ctx, electricityAgreements, codes, electricityRegion,
+						startDate, endDate, c.fetchTariffRatesCached)
+				},
+				// Standing charges can change daily on some products, so prefer the Products API
+				func(ctx context.Context) {
+					electricityStanding, electricityStandingErr = c.fetchAgreementRates(ctx, electricityAgreements, codes, electricityRegion,
+						startDate, endDate, c.fetchStandingChargesCached)
+				},
+			)
+		}
+	}
+	if len(exportConsumption) > 0 && len(meters.ExportAgreements) > 0 {
+		if codes, err := productCode(meters.ExportAgreements); err == nil {
+			c.logger.Info("Fetching time-varying export rates from Products API")
+			tasks = append(tasks, func(ctx context.Context) {
+				exportRates, exportRatesErr = c.fetchAgreementRates(ctx, meters.ExportAgreements, codes, exportRegion,
+					startDate, endDate, c.fetchTariffRatesCached)
+			})
+		}
+	}
+	if haveGas && gasErr == nil && len(gasAgreements) > 0 {
+		// Tracker and other products price gas daily, so prefer the Products API
+		if codes, err := productCode(gasAgreements); err == nil {
+			c.logger.Info("Fetching time-varying gas rates from Products API")
+			tasks = append(tasks,
+				func(ctx context.Context) {
+					gasRates, gasRatesErr = c.fetchAgreementRates(ctx, gasAgreements, codes, gasRegion,
+						startDate, endDate, c.fetchGasTariffRatesCached)
+				},
+				func(ctx context.Context) {
+					gasStanding, gasStandingErr = c.fetchAgreementRates(ctx, gasAgreements, codes, gasRegion,
+						startDate, endDate, c.fetchGasStandingChargesCached)
+				},
+			)
+		}
+	}
+	if err := c.runPhase(ctx, "rates", tasks); err != nil {
+		return nil, err
+	}
+
+	// Merge electricity
+	if haveElectricity {
+		if electricityErr != nil {
+			c.logger.Warn("Failed to fetch electricity consumption", "error", electricityErr)
+		} else {
+			consumptions := electricityReadings.Consumptions
+			data.Region = electricityRegion
+			data.RegionSource = electricityRegionSrc
+			data.PricingModel = reportedModel.Describe()
 
 			// Calculate costs using tariff data
-			if len(agreements) > 0 {
-				tariffName := agreements[0].Tariff.DisplayName
-
-				// Try to fetch time-varying rates from Products API for more accurate costs
-				productCode, err := c.fetchProductCodeCached(tariffName)
-				if err == nil {
-					c.logger.Info("Fetching time-varying electricity rates from Products API")
-					rates, err := c.fetchTariffRatesCached(productCode, startDate, endDate)
-					if err == nil {
-						consumptions = CalculateConsumptionCostsWithRates(consumptions, rates)
-						c.logger.Info("Calculated electricity costs using time-varying rates", "rates_count", len(rates))
+			var unitRates []TariffRate
+			if len(electricityAgreements) > 0 {
+				if _, err := productCode(electricityAgreements); err == nil {
+					if electricityRatesErr == nil {
+						unitRates = electricityRates
+						consumptions = CalculateConsumptionCostsWithRates(consumptions, unitRates)
+						c.logger.Info("Calculated electricity costs using time-varying rates", "rates_count", len(unitRates))
 					} else {
-						c.logger.Warn("Failed to fetch tariff rates, using simple tariff calculation", "error", err)
-						consumptions = CalculateConsumptionCosts(consumptions, agreements)
+						c.logger.Warn("Failed to fetch tariff rates, using simple tariff calculation", "error", electricityRatesErr)
+						consumptions = CalculateConsumptionCosts(consumptions, electricityAgreements, pricingModel)
 						c.logger.Info("Calculated electricity costs from tariff data")
 					}
+
+					if electricityStandingErr != nil {
+						c.logger.Warn("Failed to fetch standing charges, using agreement standing charge", "error", electricityStandingErr)
+					}
 				} else {
 					c.logger.Warn("Failed to fetch product code, using simple tariff calculation", "error", err)
-					consumptions = CalculateConsumptionCosts(consumptions, agreements)
+					consumptions = CalculateConsumptionCosts(consumptions, electricityAgreements, pricingModel)
 					c.logger.Info("Calculated electricity costs from tariff data")
 				}
 			}
 
+			if intelligent {
+				if dispatchErr != nil {
+					c.logger.Warn("Failed to fetch completed dispatches", "error", dispatchErr)
+				}
+				data.Dispatches = dispatches
+				if len(data.Dispatches) > 0 {
+					offPeakRate := func(t time.Time) float64 {
+						if len(unitRates) > 0 {
+							return lowestRateAround(t, unitRates)
+						}
+						if tariff := findActiveTariff(t, electricityAgreements); tariff != nil {
+							return tariff.RateForBand(BandOffPeak)
+						}
+						return 0
+					}
+					data.DispatchKWh, data.DispatchSavings = ApplyDispatchPricing(consumptions, data.Dispatches, offPeakRate)
+					c.logger.Info("Repriced smart-charge dispatches at off-peak",
+						"dispatches", len(data.Dispatches),
+						"kwh", fmt.Sprintf("%.2f", data.DispatchKWh),
+						"saving_pence", fmt.Sprintf("%.0f", data.DispatchSavings),
+					)
+				}
+			}
+
+			data.ElectricityStandingCharges = CalculateStandingCharges(startDate, endDate, electricityAgreements, electricityStanding)
+
 			data.ElectricityConsumption = consumptions
-			data.ElectricityAgreements = agreements
+			data.ElectricityAgreements = electricityAgreements
 			c.logger.Info("Electricity data collected",
 				"consumptions", len(consumptions),
-				"agreements", len(agreements),
+				"agreements", len(electricityAgreements),
+				"standing_charge_days", len(data.ElectricityStandingCharges),
 			)
-
-			// Save to storage
 		}
-	} else {
-		c.logger.Info("Skipping electricity consumption (not configured)")
 	}
 
-	// Fetch export data if export meter was discovered
-	if exportMPAN != "" && len(exportSerials) > 0 {
-		c.logger.Info("Fetching solar/battery export data", "serials_to_try", len(exportSerials))
-
-		var exports []Consumption
-		var fetchErr error
-
-		// Try each serial number until we find one with data
-		for _, serial := range exportSerials {
-			exports, _, fetchErr = c.client.FetchElectricityConsumption(
-				exportMPAN,
-				serial,
-				startDate,
-				endDate,
-			)
-			if fetchErr == nil && len(exports) > 0 {
-				c.logger.Info("Found export data", "serial", serial, "records", len(exports))
-				break
-			}
-		}
+	// Merge export
+	if haveExport {
+		if exportErr != nil {
+			c.logger.Warn("Failed to fetch export data", "error", exportErr)
+		} else if len(exportConsumption) > 0 {
+			exports := exportConsumption
 
-		if fetchErr != nil {
-			c.logger.Warn("Failed to fetch export data", "error", fetchErr)
-		} else if len(exports) > 0 {
 			// Calculate export earnings using tariff rates
-			if len(exportAgreements) > 0 {
-				tariffName := exportAgreements[0].Tariff.DisplayName
-
-				// Try to fetch time-varying rates from Products API
-				productCode, err := c.fetchProductCodeCached(tariffName)
-				if err == nil {
-					c.logger.Info("Fetching time-varying export rates from Products API")
-					rates, err := c.fetchTariffRatesCached(productCode, startDate, endDate)
-					if err == nil {
-						exports = CalculateConsumptionCostsWithRates(exports, rates)
-						c.logger.Info("Calculated export earnings using time-varying rates", "rates_count", len(rates))
+			if len(meters.ExportAgreements) > 0 {
+				if _, err := productCode(meters.ExportAgreements); err == nil {
+					if exportRatesErr == nil {
+						exports = CalculateConsumptionCostsWithRates(exports, exportRates)
+						c.logger.Info("Calculated export earnings using time-varying rates", "rates_count", len(exportRates))
 					} else {
-						c.logger.Warn("Failed to fetch export tariff rates", "error", err)
+						c.logger.Warn("Failed to fetch export tariff rates", "error", exportRatesErr)
 					}
 				}
 			}
 
 			data.ElectricityExport = exports
-			data.ElectricityExportAgreements = exportAgreements
+			data.ElectricityExportAgreements = meters.ExportAgreements
 			c.logger.Info("Export data collected",
 				"exports", len(exports),
-				"agreements", len(exportAgreements),
+				"agreements", len(meters.ExportAgreements),
 			)
 		} else {
 			c.logger.Warn("No export data found for any serial number")
 		}
 	}
 
-	// Fetch gas consumption if configured
-	if c.config.GasMPRN != "" && c.config.GasSerial != "" {
-		c.logger.Info("Fetching gas consumption data")
-		consumptions, _, err := c.client.FetchGasConsumption(
-			c.config.GasMPRN,
-			c.config.GasSerial,
-			startDate,
-			endDate,
-		)
-		if err != nil {
-			c.logger.Warn("Failed to fetch gas consumption", "error", err)
-			// Continue with other data collection
+	// Merge gas
+	if haveGas {
+		if gasErr != nil {
+			c.logger.Warn("Failed to fetch gas consumption", "error", gasErr)
 		} else {
-			// Get agreements from account details
-			var agreements []Agreement
-			for _, prop := range account.Properties {
-				for _, gmp := range prop.GasMeterPoints {
-					if gmp.MPRN == c.config.GasMPRN {
-						agreements = gmp.Agreements
-						break
+			consumptions := gasReadings.Consumptions
+
+			// Calculate costs using tariff data
+			if len(gasAgreements) > 0 {
+				if _, err := productCode(gasAgreements); err == nil {
+					if gasRatesErr == nil {
+						consumptions = CalculateConsumptionCostsWithRates(consumptions, gasRates)
+						c.logger.Info("Calculated gas costs using time-varying rates", "rates_count", len(gasRates))
+					} else {
+						c.logger.Warn("Failed to fetch gas tariff rates, using simple tariff calculation", "error", gasRatesErr)
+						consumptions = CalculateConsumptionCosts(consumptions, gasAgreements, FlatPricing)
+						c.logger.Info("Calculated gas costs from tariff data")
+					}
+
+					if gasStandingErr != nil {
+						c.logger.Warn("Failed to fetch gas standing charges, using agreement standing charge", "error", gasStandingErr)
 					}
+				} else {
+					c.logger.Warn("Failed to fetch gas product code, using simple tariff calculation", "error", err)
+					consumptions = CalculateConsumptionCosts(consumptions, gasAgreements, FlatPricing)
+					c.logger.Info("Calculated gas costs from tariff data")
 				}
 			}
 
-			// Calculate costs using tariff data
-			if len(agreements) > 0 {
-				consumptions = CalculateConsumptionCosts(consumptions, agreements)
-				c.logger.Info("Calculated gas costs from tariff data")
-			}
+			data.GasStandingCharges = CalculateStandingCharges(startDate, endDate, gasAgreements, gasStanding)
 
 			data.GasConsumption = consumptions
-			data.GasAgreements = agreements
+			data.GasAgreements = gasAgreements
 			c.logger.Info("Gas data collected",
 				"consumptions", len(consumptions),
-				"agreements", len(agreements),
+				"agreements", len(gasAgreements),
+				"standing_charge_days", len(data.GasStandingCharges),
 			)
-
-			// Save to storage
 		}
-	} else {
-		c.logger.Info("Skipping gas consumption (not configured)")
 	}
 
-	// Save complete collected data
-
-	// Log summary of collected data
-	c.logger.Info("Data collection completed successfully")
-	c.logger.Info("Collection summary",
+	c.logger.Info("Property data collected",
+		"property", property.Label(),
 		"electricity_records", len(data.ElectricityConsumption),
 		"export_records", len(data.ElectricityExport),
 		"gas_records", len(data.GasConsumption),
-		"electricity_agreements", len(data.ElectricityAgreements),
-		"export_agreements", len(data.ElectricityExportAgreements),
-		"gas_agreements", len(data.GasAgreements),
 	)
 
 	return data, nil
 }
 
+// stitchSerials merges the readings fetched for each serial on a meter point.
+// Serials that failed are skipped as long as another returned readings.
+func (c *Collector) stitchSerials(fuelType string, series []serialReadings, errs []error) (StitchedReadings, error) {
+	if err := firstFetchError(series, errs); err != nil {
+		return StitchedReadings{}, err
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			c.logger.Warn("Failed to fetch readings for meter serial", "fuel", fuelType, "serial", series[i].Serial, "error", err)
+		}
+	}
+
+	stitched := StitchReadings(fuelType, series)
+	for _, exchange := range stitched.Exchanges {
+		c.logger.Info("Detected meter exchange",
+			"fuel", fuelType,
+			"date", londonDate(exchange.Date),
+			"old_serial", exchange.OldSerial,
+			"new_serial", exchange.NewSerial,
+		)
+	}
+	if stitched.Overlaps > 0 {
+		c.logger.Debug("Resolved overlapping readings between serials", "fuel", fuelType, "slots", stitched.Overlaps)
+	}
+
+	return stitched, nil
+}
+
+// convertGasSerials converts each gas serial's readings to kWh where the meter
+// reports m³, returning the unit the readings were metered in. The unit is
+// GasUnitMixed when serials on the meter point report in different units, with
+// the source of the m³ serial's unit.
+func (c *Collector) convertGasSerials(property Property, meters propertyMeters, series []serialReadings) (string, string, error) {
+	var gasMeters []Meter
+	for _, gmp := range property.GasMeterPoints {
+		if gmp.MPRN == meters.GasMPRN {
+			gasMeters = gmp.Meters
+			break
+		}
+	}
+
+	var unit, source, volumeSource string
+	mixed := false
+	for i := range series {
+		if len(series[i].Consumptions) == 0 {
+			continue
+		}
+
+		serialUnit, serialSource := ResolveGasUnit(c.config.GasUnits, findMeter(gasMeters, series[i].Serial))
+		series[i].Unit = serialUnit
+		if unit == "" {
+			unit, source = serialUnit, serialSource
+		} else if serialUnit != unit {
+			mixed = true
+		}
+
+		// SMETS2 meters report volume, which must be in kWh before pricing
+		if serialUnit != GasUnitCubicMetres {
+			continue
+		}
+		volumeSource = serialSource
+		calorificValues, err := c.config.CalorificValues()
+		if err != nil {
+			return "", "", &ConfigError{Field: "gas_calorific_values", Message: err.Error()}
+		}
+		series[i].Consumptions = ConvertGasToKWh(series[i].Consumptions, calorificValues)
+		c.logger.Info("Converted gas readings from m³ to kWh",
+			"serial", series[i].Serial,
+			"unit_source", serialSource,
+			"calorific_value", c.config.GasCalorificValue,
+			"dated_values", len(c.config.GasCalorificValues),
+		)
+	}
+
+	if mixed {
+		c.logger.Info("Gas serials report in different units, keeping the metered volume for those reporting m³")
+		return GasUnitMixed, volumeSource, nil
+	}
+	return unit, source, nil
+}
+
+// runPhase runs one batch of independent fetches on the worker pool and returns
+// an error if collection was cancelled or ran out of time part way through
+func (c *Collector) runPhase(ctx context.Context, phase string, tasks []task) error {
+	started := time.Now()
+	if err := runTasks(ctx, c.config.CollectionWorkers, tasks); err != nil {
+		return fmt.Errorf("data collection interrupted while fetching %s data: %w", phase, err)
+	}
+	c.logger.Debug("Collection phase completed",
+		"phase", phase,
+		"requests", len(tasks),
+		"duration", time.Since(started).Round(time.Millisecond),
+	)
+	return nil
+}
+
 // containsIgnoreCase checks if a string contains a substring (case-insensitive)
 func containsIgnoreCase(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
 
-// fetchProductCodeCached fetches product code with caching (cache for 24 hours)
-func (c *Collector) fetchProductCodeCached(tariffName string) (string, error) {
+// fetchAccountCached fetches account details with caching (cache for 1 hour)
+func (c *Collector) fetchAccountCached(ctx context.Context) (*Account, error) {
+	cacheKey := fmt.Sprintf("account_%s", c.config.AccountID)
+	var account *Account
+	cached, err := c.storage.LoadCache(cacheKey, &account)
+	if err != nil {
+		c.logger.Warn("Failed to load account from cache", "error", err)
+	}
+
+	if !cached {
+		account, err = c.client.FetchAccountDetails(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// Cache account details for 1 hour
+		if err := c.storage.SaveCache(cacheKey, account, 1*time.Hour); err != nil {
+			c.logger.Warn("Failed to cache account details", "error", err)
+		}
+	} else {
+		c.logger.Info("Loaded account details from cache")
+	}
+
+	return account, nil
+}
+
+// fetchProductCodeCached fetches product code with caching (cache for 24 hours).
+// Prefix matches are cached separately so they never stand in for an exact lookup.
+func (c *Collector) fetchProductCodeCached(ctx context.Context, tariffName string, matchPrefix bool) (string, error) {
 	cacheKey := fmt.Sprintf("product_code_%s", strings.ReplaceAll(tariffName, " ", "_"))
+	if matchPrefix {
+		cacheKey += "_prefix"
+	}
 	var productCode string
 	cached, err := c.storage.LoadCache(cacheKey, &productCode)
 	if err != nil {
@@ -283,7 +726,7 @@ func (c *Collector) fetchProductCodeCached(tariffName string) (string, error) {
 	}
 
 	if !cached {
-		productCode, err = c.client.FetchProductCode(tariffName)
+		productCode, err = c.client.FetchProductCode(ctx, tariffName, matchPrefix)
 		if err != nil {
 			return "", err
 		}
@@ -298,11 +741,223 @@ func (c *Collector) fetchProductCodeCached(tariffName string) (string, error) {
 	return productCode, nil
 }
 
+// fetchStatementsCached fetches recent statements with caching (cache for 12 hours)
+func (c *Collector) fetchStatementsCached(ctx context.Context) ([]Statement, error) {
+	cacheKey := fmt.Sprintf("statements_%s", c.config.AccountID)
+	var statements []Statement
+	cached, err := c.storage.LoadCache(cacheKey, &statements)
+	if err != nil {
+		c.logger.Warn("Failed to load statements from cache", "error", err)
+	}
+
+	if !cached {
+		statements, err = c.client.FetchStatements(ctx, statementHistoryLimit)
+		if err != nil {
+			return nil, err
+		}
+		// Statements are issued at most monthly, so a 12 hour cache is plenty fresh
+		if err := c.storage.SaveCache(cacheKey, statements, 12*time.Hour); err != nil {
+			c.logger.Warn("Failed to cache statements", "error", err)
+		}
+	} else {
+		c.logger.Debug("Loaded statements from cache", "count", len(statements))
+	}
+
+	return statements, nil
+}
+
+// fetchPaymentsCached fetches recent payments with caching (cache for 12 hours)
+func (c *Collector) fetchPaymentsCached(ctx context.Context) ([]Payment, error) {
+	cacheKey := fmt.Sprintf("payments_%s", c.config.AccountID)
+	var payments []Payment
+	cached, err := c.storage.LoadCache(cacheKey, &payments)
+	if err != nil {
+		c.logger.Warn("Failed to load payments from cache", "error", err)
+	}
+
+	if !cached {
+		payments, err = c.client.FetchPayments(ctx, transactionHistoryLimit)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.storage.SaveCache(cacheKey, payments, 12*time.Hour); err != nil {
+			c.logger.Warn("Failed to cache payments", "error", err)
+		}
+	} else {
+		c.logger.Debug("Loaded payments from cache", "count", len(payments))
+	}
+
+	return payments, nil
+}
+
+// fetchPaymentScheduleCached fetches the active payment schedule with caching (cache for 12 hours)
+func (c *Collector) fetchPaymentScheduleCached(ctx context.Context) (*PaymentSchedule, error) {
+	cacheKey := fmt.Sprintf("payment_schedule_%s", c.config.AccountID)
+	var schedule *PaymentSchedule
+	cached, err := c.storage.LoadCache(cacheKey, &schedule)
+	if err != nil {
+		c.logger.Warn("Failed to load payment schedule from cache", "error", err)
+	}
+
+	if !cached {
+		schedule, err = c.client.FetchPaymentSchedule(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.storage.SaveCache(cacheKey, schedule, 12*time.Hour); err != nil {
+			c.logger.Warn("Failed to cache payment schedule", "error", err)
+		}
+	} else {
+		c.logger.Debug("Loaded payment schedule from cache")
+	}
+
+	return schedule, nil
+}
+
+// collectDispatches returns the completed dispatches in the period, leaving out
+// bump charges, which aren't billed at the off-peak rate. The API only keeps a few days of
+// history, so each day's dispatches are merged into a long-lived per-day cache
+// entry and the period is assembled from those. A fetch error is returned
+// alongside whatever the cache still holds.
+func (c *Collector) collectDispatches(ctx context.Context, startDate, endDate time.Time) ([]Dispatch, error) {
+	byStart := make(map[int64]Dispatch)
+
+	fetched, fetchErr := c.client.FetchCompletedDispatches(ctx)
+
+	byDay := make(map[string][]Dispatch)
+	for _, dispatch := range fetched {
+		day := dispatch.Start.In(london).Format("2006-01-02")
+		byDay[day] = append(byDay[day], dispatch)
+		byStart[dispatch.Start.Unix()] = dispatch
+	}
+
+	for day, dispatches := range byDay {
+		cacheKey := fmt.Sprintf("dispatches_%s", day)
+
+		var cachedDay []Dispatch
+		if _, err := c.storage.LoadCache(cacheKey, &cachedDay); err != nil {
+			c.logger.Warn("Failed to load dispatches from cache", "day", day, "error", err)
+		}
+
+		merged := mergeDispatches(cachedDay, dispatches)
+		if err := c.storage.SaveCache(cacheKey, merged, dispatchCacheTTL); err != nil {
+			c.logger.Warn("Failed to cache dispatches", "day", day, "error", err)
+		}
+	}
+
+	// Assemble the period from the per-day cache
+	first := startDate.In(london)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, london)
+	for ; day.Before(endDate); day = day.AddDate(0, 0, 1) {
+		var cachedDay []Dispatch
+		cached, err := c.storage.LoadCache(fmt.Sprintf("dispatches_%s", day.Format("2006-01-02")), &cachedDay)
+		if err != nil || !cached {
+			continue
+		}
+		for _, dispatch := range cachedDay {
+			byStart[dispatch.Start.Unix()] = dispatch
+		}
+	}
+
+	var dispatches []Dispatch
+	for _, dispatch := range byStart {
+		if dispatch.Source == dispatchSourceBumpCharge {
+			continue
+		}
+		if dispatch.End.After(startDate) && dispatch.Start.Before(endDate) {
+			dispatches = append(dispatches, dispatch)
+		}
+	}
+	sort.Slice(dispatches, func(i, j int) bool {
+		return dispatches[i].Start.Before(dispatches[j].Start)
+	})
+
+	c.logger.Debug("Assembled dispatch history", "fetched", len(fetched), "in_period", len(dispatches))
+	return dispatches, fetchErr
+}
+
+// electricityAgreementsFor returns the agreements on one of a property's
+// electricity meter points
+func electricityAgreementsFor(property Property, mpan string) []Agreement {
+	for _, emp := range property.ElectricityMeterPoints {
+		if emp.MPAN == mpan {
+			return emp.Agreements
+		}
+	}
+	return nil
+}
+
+// onIntelligentTariff reports whether the current agreement is Intelligent
+// Octopus, which bills smart-charge dispatches outside the normal window at off-peak
+func onIntelligentTariff(agreements []Agreement) bool {
+	tariff := currentTariff(agreements)
+	return tariff != nil && containsIgnoreCase(tariff.DisplayName, "intelligent")
+}
+
+// mergeDispatches combines two dispatch lists, keeping one entry per start time
+func mergeDispatches(existing, fresh []Dispatch) []Dispatch {
+	seen := make(map[int64]bool)
+	var merged []Dispatch
+	for _, dispatch := range append(append([]Dispatch{}, fresh...), existing...) {
+		if seen[dispatch.Start.Unix()] {
+			continue
+		}
+		seen[dispatch.Start.Unix()] = true
+		merged = append(merged, dispatch)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Start.Before(merged[j].Start)
+	})
+	return merged
+}
+
 // fetchTariffRatesCached fetches tariff rates with caching (cache for 6 hours)
-func (c *Collector) fetchTariffRatesCached(productCode string, startDate, endDate time.Time) ([]TariffRate, error) {
-	// Cache key includes date range since rates are time-specific
-	cacheKey := fmt.Sprintf("tariff_rates_%s_%s_%s",
+func (c *Collector) fetchTariffRatesCached(ctx context.Context, productCode, region string, startDate, endDate time.Time) ([]TariffRate, error) {
+	return c.fetchRatesCached(ctx, "tariff_rates", productCode, region, startDate, endDate, c.client.FetchElectricityTariffRates)
+}
+
+// fetchStandingChargesCached fetches standing charges with caching (cache for 6 hours)
+func (c *Collector) fetchStandingChargesCached(ctx context.Context, productCode, region string, startDate, endDate time.Time) ([]TariffRate, error) {
+	return c.fetchRatesCached(ctx, "standing_charges", productCode, region, startDate, endDate, c.client.FetchElectricityStandingCharges)
+}
+
+// fetchGasTariffRatesCached fetches gas tariff rates with caching (cache for 6 hours)
+func (c *Collector) fetchGasTariffRatesCached(ctx context.Context, productCode, region string, startDate, endDate time.Time) ([]TariffRate, error) {
+	return c.fetchRatesCached(ctx, "gas_tariff_rates", productCode, region, startDate, endDate, c.client.FetchGasTariffRates)
+}
+
+// fetchGasStandingChargesCached fetches gas standing charges with caching (cache for 6 hours)
+func (c *Collector) fetchGasStandingChargesCached(ctx context.Context, productCode, region string, startDate, endDate time.Time) ([]TariffRate, error) {
+	return c.fetchRatesCached(ctx, "gas_standing_charges", productCode, region, startDate, endDate, c.client.FetchGasStandingCharges)
+}
+
+// fetchAgreementRates fetches rates from each agreement's own product for the
+// part of the period it was in force, so usage after a tariff switch is priced
+// at the new product's rates. The newest agreement's rates come first, so a
+// slot starting at a switch takes the new rates.
+func (c *Collector) fetchAgreementRates(ctx context.Context, agreements []Agreement, productCodes map[string]string, region string,
+	startDate, endDate time.Time, fetch func(ctx context.Context, productCode, region string, startDate, endDate time.Time) ([]TariffRate, error)) ([]TariffRate, error) {
+	spans := agreementSpans(agreements, startDate, endDate)
+	var rates []TariffRate
+	for i := len(spans) - 1; i >= 0; i-- {
+		span := spans[i]
+		fetched, err := fetch(ctx, productCodes[span.Tariff.DisplayName], region, span.From, span.To)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, clipRates(fetched, span.From, span.To)...)
+	}
+	return rates, nil
+}
+
+// fetchRatesCached fetches a Products API rate listing through the cache
+func (c *Collector) fetchRatesCached(ctx context.Context, kind, productCode, region string, startDate, endDate time.Time,
+	fetch func(ctx context.Context, productCode, region string, startDate, endDate time.Time) ([]TariffRate, error)) ([]TariffRate, error) {
+	// Cache key includes region and date range since rates are region- and time-specific
+	cacheKey := fmt.Sprintf("%s_%s_%s_%s_%s",
+		kind,
 		productCode,
+		region,
 		startDate.Format("2006-01-02"),
 		endDate.Format("2006-01-02"),
 	)
@@ -310,36 +965,99 @@ func (c *Collector) fetchTariffRatesCached(productCode string, startDate, endDat
 	var rates []TariffRate
 	cached, err := c.storage.LoadCache(cacheKey, &rates)
 	if err != nil {
-		c.logger.Warn("Failed to load tariff rates from cache", "error", err)
+		c.logger.Warn("Failed to load rates from cache", "kind", kind, "error", err)
 	}
 
 	if !cached {
-		rates, err = c.client.FetchElectricityTariffRates(productCode, startDate, endDate)
+		rates, err = fetch(ctx, productCode, region, startDate, endDate)
 		if err != nil {
 			return nil, err
 		}
-		// Cache tariff rates for 6 hours (rates update periodically)
+		// Cache rates for 6 hours (rates update periodically)
 		if err := c.storage.SaveCache(cacheKey, rates, 6*time.Hour); err != nil {
-			c.logger.Warn("Failed to cache tariff rates", "error", err)
+			c.logger.Warn("Failed to cache rates", "kind", kind, "error", err)
 		}
 	} else {
-		c.logger.Debug("Loaded tariff rates from cache", "product", productCode, "count", len(rates))
+		c.logger.Debug("Loaded rates from cache", "kind", kind, "product", productCode, "region", region, "count", len(rates))
 	}
 
 	return rates, nil
 }
 
-// discoverMeters auto-discovers meters from account details if not explicitly configured
-// Returns export meter details (MPAN, serials, agreements) if found
-func (c *Collector) discoverMeters(account *Account) (string, []string, []Agreement) {
-	if account == nil || len(account.Properties) == 0 {
-		return "", nil, nil
+// selectProperties returns the account properties to collect, honouring the
+// properties setting in config.yaml
+func (c *Collector) selectProperties(account *Account) ([]Property, error) {
+	if len(account.Properties) == 0 {
+		// Meters set in config can still be collected without property details
+		return []Property{{}}, nil
+	}
+
+	selected, unmatched := SelectProperties(account.Properties, c.config.Properties)
+	for _, selector := range unmatched {
+		c.logger.Warn("No property on the account matches the configured selector", "selector", selector)
+	}
+	if len(selected) == 0 {
+		return nil, &ConfigError{
+			Field:   "properties",
+			Message: fmt.Sprintf("none of %q match a property on account %s", c.config.Properties, account.Number),
+		}
+	}
+
+	if len(selected) > 1 {
+		c.logger.Info("Collecting multiple properties", "selected", len(selected), "on_account", len(account.Properties))
+	}
+	return selected, nil
+}
+
+// configuredPropertyIndex returns the index of the property that the meters in
+// config.yaml belong to, or -1 when none are configured. Meters that can't be
+// found on any selected property are assumed to belong to the first.
+func (c *Collector) configuredPropertyIndex(properties []Property) int {
+	if c.config.ElectricityMPAN == "" && c.config.GasMPRN == "" {
+		return -1
+	}
+
+	for i, property := range properties {
+		for _, emp := range property.ElectricityMeterPoints {
+			if c.config.ElectricityMPAN != "" && emp.MPAN == c.config.ElectricityMPAN {
+				return i
+			}
+		}
+		for _, gmp := range property.GasMeterPoints {
+			if c.config.GasMPRN != "" && gmp.MPRN == c.config.GasMPRN {
+				return i
+			}
+		}
 	}
+	return 0
+}
 
-	property := account.Properties[0] // Use first property
+// propertyMeters identifies the meters collected for one property
+type propertyMeters struct {
+	ElectricityMPAN    string
+	ElectricitySerial  string
+	ElectricitySerials []string // Every serial on the meter point, ElectricitySerial first
+	GasMPRN            string
+	GasSerial          string
+	GasSerials         []string // Every serial on the meter point, GasSerial first
+	ExportMPAN         string
+	ExportSerials      []string
+	ExportAgreements   []Agreement
+}
+
+// discoverMeters works out which meters to collect for a property, starting from
+// any configured in config.yaml and auto-discovering the rest from account details
+func (c *Collector) discoverMeters(property Property, useConfigured bool) propertyMeters {
+	var meters propertyMeters
+	if useConfigured {
+		meters.ElectricityMPAN = c.config.ElectricityMPAN
+		meters.ElectricitySerial = c.config.ElectricitySerial
+		meters.GasMPRN = c.config.GasMPRN
+		meters.GasSerial = c.config.GasSerial
+	}
 
 	// Auto-discover electricity meters if not configured
-	if c.config.ElectricityMPAN == "" && len(property.ElectricityMeterPoints) > 0 {
+	if meters.ElectricityMPAN == "" && len(property.ElectricityMeterPoints) > 0 {
 		// Look for import meter first (primary consumption meter)
 		var importMeter *ElectricityMeterPoint
 		var exportMeter *ElectricityMeterPoint
@@ -356,79 +1074,88 @@ func (c *Collector) discoverMeters(account *Account) (string, []string, []Agreem
 			}
 		}
 
-		// Prefer import meter for consumption analysis
+		// Prefer import meter for consumption analysis, falling back to the first meter
 		if importMeter != nil {
-			c.config.ElectricityMPAN = importMeter.MPAN
+			meters.ElectricityMPAN = importMeter.MPAN
 			if len(importMeter.Meters) > 0 {
-				c.config.ElectricitySerial = importMeter.Meters[0].SerialNumber
+				meters.ElectricitySerial = importMeter.Meters[0].SerialNumber
 			}
 			c.logger.Info("Auto-discovered electricity import meter",
-				"mpan", c.config.ElectricityMPAN,
-				"serial", c.config.ElectricitySerial,
+				"property", property.Label(),
+				"mpan", meters.ElectricityMPAN,
+				"serial", meters.ElectricitySerial,
 				"tariff", importMeter.Agreements[0].Tariff.DisplayName,
 			)
-		} else if len(property.ElectricityMeterPoints) > 0 {
-			// Fallback to first meter if no import meter found
+		} else {
 			emp := &property.ElectricityMeterPoints[0]
-			c.config.ElectricityMPAN = emp.MPAN
+			meters.ElectricityMPAN = emp.MPAN
 			if len(emp.Meters) > 0 {
-				c.config.ElectricitySerial = emp.Meters[0].SerialNumber
+				meters.ElectricitySerial = emp.Meters[0].SerialNumber
 			}
 			c.logger.Info("Auto-discovered electricity meter",
-				"mpan", c.config.ElectricityMPAN,
-				"serial", c.config.ElectricitySerial,
+				"property", property.Label(),
+				"mpan", meters.ElectricityMPAN,
+				"serial", meters.ElectricitySerial,
 			)
 		}
 
-		// Return export meter details if found
 		if exportMeter != nil {
 			// Collect ALL serial numbers from the export meter
 			// Some meters have multiple serials and we need to try them all
-			exportSerials := make([]string, 0, len(exportMeter.Meters))
+			meters.ExportMPAN = exportMeter.MPAN
+			meters.ExportAgreements = exportMeter.Agreements
 			for _, meter := range exportMeter.Meters {
 				if meter.SerialNumber != "" {
-					exportSerials = append(exportSerials, meter.SerialNumber)
+					meters.ExportSerials = append(meters.ExportSerials, meter.SerialNumber)
 				}
 			}
 			c.logger.Info("Detected solar/battery export meter",
+				"property", property.Label(),
 				"mpan", exportMeter.MPAN,
-				"serials", exportSerials,
+				"serials", meters.ExportSerials,
 				"tariff", exportMeter.Agreements[0].Tariff.DisplayName,
 			)
-			// Will be used to fetch export data
-			defer func() {}() // Prevent issues with return in the middle
-			exportMPAN := exportMeter.MPAN
-			exportAgmts := exportMeter.Agreements
-
-			// Auto-discover gas meter if not configured
-			if c.config.GasMPRN == "" && len(property.GasMeterPoints) > 0 {
-				gmp := &property.GasMeterPoints[0]
-				c.config.GasMPRN = gmp.MPRN
-				if len(gmp.Meters) > 0 {
-					c.config.GasSerial = gmp.Meters[0].SerialNumber
-				}
-				c.logger.Info("Auto-discovered gas meter",
-					"mprn", c.config.GasMPRN,
-					"serial", c.config.GasSerial,
-				)
-			}
-
-			return exportMPAN, exportSerials, exportAgmts
 		}
 	}
 
 	// Auto-discover gas meter if not configured
-	if c.config.GasMPRN == "" && len(property.GasMeterPoints) > 0 {
+	if meters.GasMPRN == "" && len(property.GasMeterPoints) > 0 {
 		gmp := &property.GasMeterPoints[0]
-		c.config.GasMPRN = gmp.MPRN
+		meters.GasMPRN = gmp.MPRN
 		if len(gmp.Meters) > 0 {
-			c.config.GasSerial = gmp.Meters[0].SerialNumber
+			meters.GasSerial = gmp.Meters[0].SerialNumber
 		}
 		c.logger.Info("Auto-discovered gas meter",
-			"mprn", c.config.GasMPRN,
-			"serial", c.config.GasSerial,
+			"property", property.Label(),
+			"mprn", meters.GasMPRN,
+			"serial", meters.GasSerial,
+		)
+	}
+
+	// Fetch every serial on each meter point so history survives meter exchanges
+	for _, emp := range property.ElectricityMeterPoints {
+		if emp.MPAN == meters.ElectricityMPAN && meters.ElectricitySerial != "" {
+			meters.ElectricitySerials = meterSerials(meters.ElectricitySerial, emp.Meters)
+		}
+	}
+	if len(meters.ElectricitySerials) == 0 && meters.ElectricitySerial != "" {
+		meters.ElectricitySerials = []string{meters.ElectricitySerial}
+	}
+	for _, gmp := range property.GasMeterPoints {
+		if gmp.MPRN == meters.GasMPRN && meters.GasSerial != "" {
+			meters.GasSerials = meterSerials(meters.GasSerial, gmp.Meters)
+		}
+	}
+	if len(meters.GasSerials) == 0 && meters.GasSerial != "" {
+		meters.GasSerials = []string{meters.GasSerial}
+	}
+	if len(meters.ElectricitySerials) > 1 || len(meters.GasSerials) > 1 {
+		c.logger.Info("Meter points have several serials, merging their readings",
+			"property", property.Label(),
+			"electricity_serials", meters.ElectricitySerials,
+			"gas_serials", meters.GasSerials,
 		)
 	}
 
-	return "", nil, nil
+	return meters
 }
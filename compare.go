@@ -0,0 +1,277 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TariffCandidate is an alternative product to reprice usage under
+type TariffCandidate struct {
+	Name   string `yaml:"name"`
+	Import string `yaml:"import"` // Product display name or code
+	Export string `yaml:"export"` // Optional export product; current export earnings are kept when empty
+}
+
+// DefaultTariffCandidates are compared when compare_tariffs is not configured
+var DefaultTariffCandidates = []TariffCandidate{
+	{Name: "Agile", Import: "Agile Octopus", Export: "Agile Outgoing Octopus"},
+	{Name: "Go", Import: "Octopus Go"},
+	{Name: "Flux", Import: "Octopus Flux Import", Export: "Octopus Flux Export"},
+	{Name: "Cosy", Import: "Cosy Octopus"},
+	{Name: "Tracker", Import: "Octopus Tracker"},
+	{Name: "Fixed", Import: "Octopus 12M Fixed"},
+}
+
+// CompareTariffs reprices the collected electricity import and export under each
+// candidate product, including standing charges, and ranks them against the
+// current tariff. Candidates are priced concurrently on the collection worker pool.
+func (c *Collector) CompareTariffs(ctx context.Context, data *CollectedData, candidates []TariffCandidate) (*TariffComparison, error) {
+	if len(data.ElectricityConsumption) == 0 {
+		return nil, &DataError{
+			DataType: "electricity",
+			Message:  "no electricity consumption to reprice",
+		}
+	}
+
+	start, end := consumptionSpan(data.ElectricityConsumption)
+	region := data.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	comparison := &TariffComparison{
+		GeneratedAt: now(),
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Region:      region,
+		ImportKWh:   sumConsumption(data.ElectricityConsumption),
+		ExportKWh:   sumConsumption(data.ElectricityExport),
+	}
+
+	current := c.currentScenario(data, start, end)
+	comparison.Scenarios = append(comparison.Scenarios, current)
+
+	scenarios := make([]TariffScenario, len(candidates))
+	tasks := make([]task, 0, len(candidates))
+	for i, candidate := range candidates {
+		c.logger.Info("Repricing usage", "tariff", candidate.Name, "import", candidate.Import, "export", candidate.Export)
+		tasks = append(tasks, func(ctx context.Context) {
+			scenarios[i] = c.priceCandidate(ctx, data, candidate, region, start, end, current.ExportEarnings)
+		})
+	}
+	if err := c.runPhase(ctx, "tariff comparison", tasks); err != nil {
+		return nil, err
+	}
+
+	for i, candidate := range candidates {
+		scenario := scenarios[i]
+		if scenario.Error != "" {
+			c.logger.Warn("Could not price tariff", "tariff", candidate.Name, "error", scenario.Error)
+		} else if scenario.ImportCoverage < 100 {
+			c.logger.Warn("Tariff rates do not cover the whole period",
+				"tariff", candidate.Name,
+				"import_coverage", fmt.Sprintf("%.1f%%", scenario.ImportCoverage),
+			)
+		}
+		comparison.Scenarios = append(comparison.Scenarios, scenario)
+	}
+
+	for i := range comparison.Scenarios {
+		if comparison.Scenarios[i].Error == "" {
+			comparison.Scenarios[i].Delta = comparison.Scenarios[i].NetCost - current.NetCost
+		}
+	}
+
+	// Rank cheapest first; partially priced scenarios are understated so they
+	// follow the fully priced ones, and scenarios that couldn't be priced go last
+	sort.SliceStable(comparison.Scenarios, func(i, j int) bool {
+		a, b := comparison.Scenarios[i], comparison.Scenarios[j]
+		if (a.Error == "") != (b.Error == "") {
+			return a.Error == ""
+		}
+		if a.fullyPriced() != b.fullyPriced() {
+			return a.fullyPriced()
+		}
+		return a.NetCost < b.NetCost
+	})
+
+	return comparison, nil
+}
+
+// currentScenario summarises the costs already calculated for the billed tariff.
+// Standing charges are counted over the same days the candidates are charged
+// for, since the collected charges run to the end of the analysis period while
+// readings usually stop a day or two earlier.
+func (c *Collector) currentScenario(data *CollectedData, start, end time.Time) TariffScenario {
+	name := "Current tariff"
+	if tariff := currentTariff(data.ElectricityAgreements); tariff != nil && tariff.DisplayName != "" {
+		name = tariff.DisplayName
+	}
+
+	scenario := TariffScenario{
+		Name:           name,
+		Current:        true,
+		ImportCoverage: 100,
+		ExportCoverage: 100,
+	}
+
+	for _, consumption := range data.ElectricityConsumption {
+		scenario.ImportCost += consumption.Cost / 100
+	}
+	first := londonDayStart(start)
+	if first.Before(start) {
+		first = first.AddDate(0, 0, 1)
+	}
+	for _, charge := range data.ElectricityStandingCharges {
+		day := londonDayStart(charge.Date)
+		if !day.Before(first) && day.Before(end) {
+			scenario.StandingCharges += charge.Charge / 100
+		}
+	}
+	for _, export := range data.ElectricityExport {
+		scenario.ExportEarnings += export.Cost / 100
+	}
+	scenario.NetCost = scenario.ImportCost + scenario.StandingCharges - scenario.ExportEarnings
+
+	return scenario
+}
+
+// priceCandidate reprices the period under a single candidate product
+func (c *Collector) priceCandidate(ctx context.Context, data *CollectedData, candidate TariffCandidate, region string, start, end time.Time, currentExport float64) TariffScenario {
+	scenario := TariffScenario{Name: candidate.Name}
+
+	importCode, err := c.resolveProductCode(ctx, candidate.Import)
+	if err != nil {
+		scenario.Error = fmt.Sprintf("import product not found: %v", err)
+		return scenario
+	}
+	scenario.ImportProduct = importCode
+
+	rates, err := c.fetchTariffRatesCached(ctx, importCode, region, start, end)
+	if err != nil {
+		scenario.Error = fmt.Sprintf("failed to fetch unit rates: %v", err)
+		return scenario
+	}
+	if len(rates) == 0 {
+		scenario.Error = "no unit rates published for this period"
+		return scenario
+	}
+
+	cost, coverage := priceConsumption(data.ElectricityConsumption, rates)
+	scenario.ImportCost = cost / 100
+	scenario.ImportCoverage = coverage
+
+	standingRates, err := c.fetchStandingChargesCached(ctx, importCode, region, start, end)
+	if err != nil {
+		scenario.Error = fmt.Sprintf("failed to fetch standing charges: %v", err)
+		return scenario
+	}
+	for _, charge := range CalculateStandingCharges(start, end, nil, standingRates) {
+		scenario.StandingCharges += charge.Charge / 100
+	}
+
+	// Without an export product the current export tariff is assumed to stay in place
+	scenario.ExportEarnings = currentExport
+	scenario.ExportCoverage = 100
+	if candidate.Export != "" && len(data.ElectricityExport) > 0 {
+		exportCode, err := c.resolveProductCode(ctx, candidate.Export)
+		if err != nil {
+			scenario.Error = fmt.Sprintf("export product not found: %v", err)
+			return scenario
+		}
+		scenario.ExportProduct = exportCode
+
+		exportRates, err := c.fetchTariffRatesCached(ctx, exportCode, region, start, end)
+		if err != nil {
+			scenario.Error = fmt.Sprintf("failed to fetch export rates: %v", err)
+			return scenario
+		}
+
+		earnings, coverage := priceConsumption(data.ElectricityExport, exportRates)
+		scenario.ExportEarnings = earnings / 100
+		scenario.ExportCoverage = coverage
+	}
+
+	scenario.NetCost = scenario.ImportCost + scenario.StandingCharges - scenario.ExportEarnings
+	return scenario
+}
+
+// fullyPriced reports whether every import and export slot had a rate
+func (s TariffScenario) fullyPriced() bool {
+	return s.ImportCoverage >= 100 && s.ExportCoverage >= 100
+}
+
+// resolveProductCode accepts either a product code or a product display name,
+// which may be the start of a versioned name
+func (c *Collector) resolveProductCode(ctx context.Context, product string) (string, error) {
+	product = strings.TrimSpace(product)
+	if isProductCode(product) {
+		return product, nil
+	}
+	return c.fetchProductCodeCached(ctx, product, true)
+}
+
+// isProductCode reports whether s looks like a product code such as AGILE-24-10-01
+func isProductCode(s string) bool {
+	return s != "" && !strings.Contains(s, " ") && strings.Contains(s, "-") && s == strings.ToUpper(s)
+}
+
+// priceConsumption returns the cost in pence of the consumption under the given
+// rates, and the percentage of slots that had a rate
+func priceConsumption(consumptions []Consumption, rates []TariffRate) (float64, float64) {
+	if len(consumptions) == 0 {
+		return 0, 100
+	}
+
+	var cost float64
+	var priced int
+	for _, consumption := range consumptions {
+		rate := findActiveRate(consumption.StartAt, rates)
+		if rate == nil {
+			continue
+		}
+		cost += consumption.Value * rate.ValueIncVAT
+		priced++
+	}
+
+	return cost, float64(priced) / float64(len(consumptions)) * 100
+}
+
+// consumptionSpan returns the start of the earliest and end of the latest slot
+func consumptionSpan(consumptions []Consumption) (time.Time, time.Time) {
+	start, end := consumptions[0].StartAt, consumptions[0].EndAt
+	for _, consumption := range consumptions[1:] {
+		if consumption.StartAt.Before(start) {
+			start = consumption.StartAt
+		}
+		if consumption.EndAt.After(end) {
+			end = consumption.EndAt
+		}
+	}
+	return start, end
+}
+
+// sumConsumption returns the total kWh across all slots
+func sumConsumption(consumptions []Consumption) float64 {
+	var total float64
+	for _, consumption := range consumptions {
+		total += consumption.Value
+	}
+	return total
+}
@@ -17,34 +17,111 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// MaxAnalysisPeriodDays is the longest analysis period accepted; longer ranges
+// are fetched in bounded windows by the client
+const MaxAnalysisPeriodDays = 1095
+
 // Config holds the application configuration
 type Config struct {
 	// Octopus Energy credentials
 	AccountID string `yaml:"account_id"`
 	APIKey    string `yaml:"api_key"`
 
+	// Name shown for the account in household reports (defaults to the account ID)
+	Name string `yaml:"name"`
+
+	// Several accounts analysed in one run. Each entry takes the same settings as
+	// the top level and inherits any it doesn't set.
+	Accounts []yaml.Node `yaml:"accounts"`
+
+	// Properties to analyse, by property ID or part of the address (all when empty)
+	Properties []string `yaml:"properties"`
+
 	// Meter identifiers
 	ElectricityMPAN   string `yaml:"electricity_mpan"`
 	ElectricitySerial string `yaml:"electricity_serial"`
 	GasMPRN           string `yaml:"gas_mprn"`
 	GasSerial         string `yaml:"gas_serial"`
 
+	// Gas units and conversion. SMETS2 meters report m³, which is converted to
+	// kWh using the calorific value (MJ/m³) in force on each day.
+	GasUnits           string                 `yaml:"gas_units"` // m3 or kwh (detected from the meter when empty)
+	GasCalorificValue  float64                `yaml:"gas_calorific_value"`
+	GasCalorificValues []CalorificValueConfig `yaml:"gas_calorific_values"` // Dated values overriding gas_calorific_value
+
+	// Grid supply point region letter (A-P) used for Products API rates.
+	// Detected from the tariff code or MPAN when empty.
+	Region string `yaml:"region"`
+
+	// Time-of-use pricing used when costs come from agreement rates.
+	// Detected from the tariff name when empty; "custom" uses pricing_windows.
+	PricingModel    string                `yaml:"pricing_model"`
+	PricingTimezone string                `yaml:"pricing_timezone"` // local (UK time, follows BST) or utc
+	PricingWindows  []PricingWindowConfig `yaml:"pricing_windows"`
+
 	// Analysis settings
 	AnalysisPeriodDays int     `yaml:"analysis_period_days"`
 	TargetDailySpend   float64 `yaml:"target_daily_spend"`
 	AnomalyThreshold   float64 `yaml:"anomaly_threshold"`
 	DirectDebitAmount  float64 `yaml:"direct_debit_amount"`
 
+	// Budget tracking: a monthly budget in pounds (target_daily_spend × days in
+	// the month when 0), optionally carrying what's left or overspent forward
+	MonthlyBudget   float64 `yaml:"monthly_budget"`
+	BudgetCarryOver bool    `yaml:"budget_carry_over"`
+
+	// Direct Debit forecast: the balance to reach at the account anniversary,
+	// and the anniversary itself (YYYY-MM-DD, detected from agreements when empty)
+	DirectDebitTargetBalance float64 `yaml:"direct_debit_target_balance"`
+	AccountAnniversary       string  `yaml:"account_anniversary"`
+
+	// Anomaly detection models (defaults to DefaultAnomalyModels)
+	AnomalyModels     []string `yaml:"anomaly_models"`
+	AnomalyWindowDays int      `yaml:"anomaly_window_days"` // Days in the rolling and half-hourly baselines
+	AnomalyScore      float64  `yaml:"anomaly_score"`       // Robust z-score needed to flag usage
+
+	// Share (%) of peak-band import assumed movable to the cheapest band
+	LoadShiftPercent float64 `yaml:"load_shift_percent"`
+
+	// Alternative tariffs repriced by -compare-tariffs (defaults to DefaultTariffCandidates)
+	CompareTariffs []TariffCandidate `yaml:"compare_tariffs"`
+
+	// API settings
+	RetryAttempts     int `yaml:"retry_attempts"`     // Attempts per API request, including the first
+	CollectionWorkers int `yaml:"collection_workers"` // API requests made concurrently during collection
+
+	// API endpoints (override to point at a local stand-in server)
+	GraphQLEndpoint string `yaml:"graphql_endpoint"`
+	RESTAPIBase     string `yaml:"rest_api_base"`
+	WeatherAPIURL   string `yaml:"weather_api_url"`
+	ReleasesURL     string `yaml:"releases_url"`
+
+	// HTTP fixtures for offline runs
+	FixtureMode string `yaml:"fixture_mode"` // record or replay (empty disables)
+	FixturePath string `yaml:"fixture_path"` // Directory holding recorded requests and responses
+
 	// Storage
 	StoragePath string `yaml:"storage_path"`
 
 	// Debugging
 	Debug bool `yaml:"debug"`
+
+	// Resolved entries from Accounts, set by Validate
+	accounts []*Config
+}
+
+// PricingWindowConfig is a custom time-of-use window from config.yaml
+type PricingWindowConfig struct {
+	Start string `yaml:"start"` // HH:MM
+	End   string `yaml:"end"`   // HH:MM, may wrap past midnight
+	Band  string `yaml:"band"`  // off_peak, peak or standard
 }
 
 // LoadConfig loads configuration from a YAML file
@@ -53,6 +130,16 @@ func LoadConfig(path string) (*Config, error) {
 	config := &Config{
 		AnalysisPeriodDays: 90,
 		AnomalyThreshold:   50.0,
+		AnomalyWindowDays:  DefaultAnomalyWindowDays,
+		AnomalyScore:       DefaultAnomalyScore,
+		LoadShiftPercent:   DefaultLoadShiftPercent,
+		GasCalorificValue:  DefaultGasCalorificValue,
+		RetryAttempts:      DefaultRetryAttempts,
+		CollectionWorkers:  DefaultCollectionWorkers,
+		GraphQLEndpoint:    OctopusGraphQLEndpoint,
+		RESTAPIBase:        OctopusRESTAPIBase,
+		WeatherAPIURL:      OpenMeteoArchiveURL,
+		ReleasesURL:        GitHubReleasesURL,
 		StoragePath:        getDefaultStoragePath(),
 		Debug:              false,
 	}
@@ -97,6 +184,14 @@ func (c *Config) applyEnvironmentVariables() {
 	if val := os.Getenv("OCTOPUS_API_KEY"); val != "" {
 		c.APIKey = val
 	}
+	if val := os.Getenv("OCTOPUS_PROPERTIES"); val != "" {
+		c.Properties = nil
+		for _, selector := range strings.Split(val, ",") {
+			if selector = strings.TrimSpace(selector); selector != "" {
+				c.Properties = append(c.Properties, selector)
+			}
+		}
+	}
 	if val := os.Getenv("OCTOPUS_ELECTRICITY_MPAN"); val != "" {
 		c.ElectricityMPAN = val
 	}
@@ -109,17 +204,59 @@ func (c *Config) applyEnvironmentVariables() {
 	if val := os.Getenv("OCTOPUS_GAS_SERIAL"); val != "" {
 		c.GasSerial = val
 	}
+	if val := os.Getenv("OCTOPUS_GAS_UNITS"); val != "" {
+		c.GasUnits = val
+	}
+	if val := os.Getenv("OCTOPUS_GAS_CALORIFIC_VALUE"); val != "" {
+		if value, err := strconv.ParseFloat(val, 64); err == nil {
+			c.GasCalorificValue = value
+		}
+	}
+	if val := os.Getenv("OCTOPUS_REGION"); val != "" {
+		c.Region = val
+	}
+	if val := os.Getenv("OCTOPUS_PRICING_MODEL"); val != "" {
+		c.PricingModel = val
+	}
 	if val := os.Getenv("OCTOPUS_STORAGE_PATH"); val != "" {
 		c.StoragePath = val
 	}
+	if val := os.Getenv("OCTOPUS_RETRY_ATTEMPTS"); val != "" {
+		if attempts, err := strconv.Atoi(val); err == nil {
+			c.RetryAttempts = attempts
+		}
+	}
+	if val := os.Getenv("OCTOPUS_COLLECTION_WORKERS"); val != "" {
+		if workers, err := strconv.Atoi(val); err == nil {
+			c.CollectionWorkers = workers
+		}
+	}
 	if val := os.Getenv("OCTOPUS_DEBUG"); val == "true" || val == "1" {
 		c.Debug = true
 	}
 }
 
-// Validate checks if the configuration is valid
+// Validate checks if the configuration is valid. When several accounts are
+// listed, each is resolved against the top-level settings and validated in turn.
 func (c *Config) Validate() error {
 	var errors []string
+	if len(c.Accounts) > 0 {
+		errors = c.validateAccounts()
+	} else {
+		errors = c.validate()
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("configuration validation failed:\n  - %s", strings.Join(errors, "\n  - "))
+	}
+
+	return nil
+}
+
+// validate checks a single account's settings, filling in defaults, and returns
+// a description of each problem found
+func (c *Config) validate() []string {
+	var errors []string
 
 	// Required fields
 	if c.AccountID == "" {
@@ -135,14 +272,117 @@ func (c *Config) Validate() error {
 	}
 
 	// Validate analysis period
-	if c.AnalysisPeriodDays < 1 || c.AnalysisPeriodDays > 365 {
-		errors = append(errors, "analysis_period_days must be between 1 and 365")
+	if c.AnalysisPeriodDays < 1 || c.AnalysisPeriodDays > MaxAnalysisPeriodDays {
+		errors = append(errors, fmt.Sprintf("analysis_period_days must be between 1 and %d", MaxAnalysisPeriodDays))
 	}
 
 	// Validate anomaly threshold
 	if c.AnomalyThreshold < 0 || c.AnomalyThreshold > 100 {
 		errors = append(errors, "anomaly_threshold must be between 0 and 100")
 	}
+	for _, model := range c.AnomalyModels {
+		if !validAnomalyModel(strings.ToLower(strings.TrimSpace(model))) {
+			errors = append(errors, fmt.Sprintf("anomaly_models: unknown model %q (use %s)", model, strings.Join(DefaultAnomalyModels, ", ")))
+		}
+	}
+	if c.AnomalyWindowDays == 0 {
+		c.AnomalyWindowDays = DefaultAnomalyWindowDays
+	}
+	if c.AnomalyWindowDays < minBaselineDays || c.AnomalyWindowDays > MaxAnalysisPeriodDays {
+		errors = append(errors, fmt.Sprintf("anomaly_window_days must be between %d and %d", minBaselineDays, MaxAnalysisPeriodDays))
+	}
+	if c.AnomalyScore == 0 {
+		c.AnomalyScore = DefaultAnomalyScore
+	}
+	if c.AnomalyScore < 0 {
+		errors = append(errors, "anomaly_score must be positive")
+	}
+	if c.LoadShiftPercent == 0 {
+		c.LoadShiftPercent = DefaultLoadShiftPercent
+	}
+	if c.LoadShiftPercent < 0 || c.LoadShiftPercent > 100 {
+		errors = append(errors, "load_shift_percent must be between 0 and 100")
+	}
+	if c.TargetDailySpend < 0 {
+		errors = append(errors, "target_daily_spend must not be negative")
+	}
+	if c.MonthlyBudget < 0 {
+		errors = append(errors, "monthly_budget must not be negative")
+	}
+	if c.AccountAnniversary != "" {
+		if _, err := time.Parse("2006-01-02", c.AccountAnniversary); err != nil {
+			errors = append(errors, "account_anniversary must be a date in YYYY-MM-DD format")
+		}
+	}
+
+	// Validate gas conversion settings
+	if c.GasUnits != "" && normalizeGasUnit(c.GasUnits) == "" {
+		errors = append(errors, "gas_units must be \"m3\" or \"kwh\"")
+	}
+	if c.GasCalorificValue == 0 {
+		c.GasCalorificValue = DefaultGasCalorificValue
+	}
+	if !isPlausibleCalorificValue(c.GasCalorificValue) {
+		errors = append(errors, "gas_calorific_value must be between 30 and 50 MJ/m³")
+	}
+	if _, err := c.CalorificValues(); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate region override
+	if c.Region != "" {
+		c.Region = strings.ToUpper(strings.TrimSpace(c.Region))
+		if !IsValidRegion(c.Region) {
+			errors = append(errors, "region must be a GSP region letter (A-H, J-N or P)")
+		}
+	}
+
+	// Validate pricing model
+	if _, err := c.PricingModelOverride(); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate tariff comparison candidates
+	for i, candidate := range c.CompareTariffs {
+		if candidate.Name == "" || candidate.Import == "" {
+			errors = append(errors, fmt.Sprintf("compare_tariffs[%d] requires a name and an import product", i))
+		}
+	}
+
+	// Validate retry budget
+	if c.RetryAttempts < 1 || c.RetryAttempts > 10 {
+		errors = append(errors, "retry_attempts must be between 1 and 10")
+	}
+
+	// Validate collection concurrency
+	if c.CollectionWorkers < 1 || c.CollectionWorkers > MaxCollectionWorkers {
+		errors = append(errors, fmt.Sprintf("collection_workers must be between 1 and %d", MaxCollectionWorkers))
+	}
+
+	// Validate fixture settings
+	switch c.FixtureMode {
+	case "", FixtureModeRecord, FixtureModeReplay:
+	default:
+		errors = append(errors, fmt.Sprintf("fixture_mode must be %q or %q", FixtureModeRecord, FixtureModeReplay))
+	}
+	if c.FixtureMode != "" && c.FixturePath == "" {
+		errors = append(errors, "fixture_path is required when fixture_mode is set")
+	}
+
+	// Set default endpoints if empty
+	if c.GraphQLEndpoint == "" {
+		c.GraphQLEndpoint = OctopusGraphQLEndpoint
+	}
+	if c.RESTAPIBase == "" {
+		c.RESTAPIBase = OctopusRESTAPIBase
+	}
+	c.RESTAPIBase = strings.TrimSuffix(c.RESTAPIBase, "/")
+	if c.WeatherAPIURL == "" {
+		c.WeatherAPIURL = OpenMeteoArchiveURL
+	}
+	if c.ReleasesURL == "" {
+		c.ReleasesURL = GitHubReleasesURL
+	}
 
 	// Set default storage path if empty
 	if c.StoragePath == "" {
@@ -152,20 +392,164 @@ func (c *Config) Validate() error {
 	// Meter configuration is now optional - meters will be auto-discovered from account
 	// No validation needed
 
-	if len(errors) > 0 {
-		return fmt.Errorf("configuration validation failed:\n  - %s", strings.Join(errors, "\n  - "))
+	return errors
+}
+
+// validateAccounts resolves and validates every entry under accounts
+func (c *Config) validateAccounts() []string {
+	var errors []string
+
+	c.accounts = nil
+	seen := make(map[string]bool)
+	slugs := make(map[string]string)
+	for i := range c.Accounts {
+		account, err := c.resolveAccount(&c.Accounts[i])
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("accounts[%d]: %v", i, err))
+			continue
+		}
+
+		if problems := account.validate(); len(problems) > 0 {
+			for _, problem := range problems {
+				errors = append(errors, fmt.Sprintf("accounts[%d]: %s", i, problem))
+			}
+			continue
+		}
+
+		if seen[account.AccountID] {
+			errors = append(errors, fmt.Sprintf("accounts[%d]: account %s is listed more than once", i, account.AccountID))
+			continue
+		}
+		seen[account.AccountID] = true
+
+		// Each account's report is named after it, so names must stay distinct
+		if other, ok := slugs[account.ReportSlug()]; ok {
+			errors = append(errors, fmt.Sprintf("accounts[%d]: name %q would share a report file with account %s", i, account.AccountLabel(), other))
+			continue
+		}
+		slugs[account.ReportSlug()] = account.AccountID
+
+		c.accounts = append(c.accounts, account)
 	}
 
-	return nil
+	return errors
+}
+
+// resolveAccount builds one account's configuration from an accounts entry,
+// starting from a copy of the top-level settings
+func (c *Config) resolveAccount(node *yaml.Node) (*Config, error) {
+	account := *c
+	account.Accounts = nil
+	account.accounts = nil
+
+	if err := node.Decode(&account); err != nil {
+		return nil, fmt.Errorf("failed to parse account: %w", err)
+	}
+	if len(account.Accounts) > 0 {
+		return nil, fmt.Errorf("accounts cannot be nested")
+	}
+
+	return &account, nil
+}
+
+// AccountConfigs returns the configuration for each account to analyse: the
+// entries under accounts once validated, or this configuration alone
+func (c *Config) AccountConfigs() []*Config {
+	if len(c.accounts) > 0 {
+		return c.accounts
+	}
+	return []*Config{c}
+}
+
+// AccountLabel returns the account's configured name, or its account ID
+func (c *Config) AccountLabel() string {
+	if name := strings.TrimSpace(c.Name); name != "" {
+		return name
+	}
+	return c.AccountID
+}
+
+// ReportSlug names the account's own report file: its label in lowercase
+// letters, digits and hyphens, or its account ID when the label has none
+func (c *Config) ReportSlug() string {
+	if slug := slugify(c.AccountLabel()); slug != "" {
+		return slug
+	}
+	return slugify(c.AccountID)
+}
+
+// PricingModelOverride returns the configured pricing model, or nil when it
+// should be detected from the tariff
+func (c *Config) PricingModelOverride() (PricingModel, error) {
+	name := strings.ToLower(strings.TrimSpace(c.PricingModel))
+	if name == "" {
+		return nil, nil
+	}
+
+	if name != PricingModelCustom {
+		model, ok := LookupPricingModel(name)
+		if !ok {
+			return nil, fmt.Errorf("pricing_model %q is not recognised (expected flat, economy7, go, intelligent_go, cosy, flux, agile or custom)", c.PricingModel)
+		}
+		return model, nil
+	}
+
+	var utc bool
+	switch strings.ToLower(strings.TrimSpace(c.PricingTimezone)) {
+	case "", "local":
+	case "utc", "gmt":
+		utc = true
+	default:
+		return nil, fmt.Errorf("pricing_timezone must be \"local\" or \"utc\"")
+	}
+
+	if len(c.PricingWindows) == 0 {
+		return nil, fmt.Errorf("pricing_windows is required when pricing_model is \"custom\"")
+	}
+
+	windows := make([]TimeWindow, 0, len(c.PricingWindows))
+	for i, w := range c.PricingWindows {
+		window, err := ParseTimeWindow(w.Start, w.End, RateBand(strings.ToLower(strings.TrimSpace(w.Band))))
+		if err != nil {
+			return nil, fmt.Errorf("pricing_windows[%d]: %v", i, err)
+		}
+		windows = append(windows, window)
+	}
+
+	return NewWindowPricingModel(PricingModelCustom, "Custom", utc, windows), nil
+}
+
+// CalorificValues returns the calorific value lookup used to convert gas
+// readings from m³ to kWh
+func (c *Config) CalorificValues() (*CalorificValues, error) {
+	return NewCalorificValues(c.GasCalorificValue, c.GasCalorificValues)
+}
+
+// TariffCandidates returns the products to compare against the current tariff
+func (c *Config) TariffCandidates() []TariffCandidate {
+	if len(c.CompareTariffs) > 0 {
+		return c.CompareTariffs
+	}
+	return DefaultTariffCandidates
 }
 
 // GetWarnings returns non-fatal configuration warnings
 func (c *Config) GetWarnings() []string {
 	var warnings []string
 
+	// Each listed account carries its own settings
+	if len(c.accounts) > 0 {
+		for _, account := range c.accounts {
+			for _, warning := range account.GetWarnings() {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", account.AccountLabel(), warning))
+			}
+		}
+		return warnings
+	}
+
 	// Warn about missing Direct Debit amount
 	if c.DirectDebitAmount == 0 {
-		warnings = append(warnings, "direct_debit_amount not set - payment recommendations will be limited")
+		warnings = append(warnings, "direct_debit_amount not set - it will be detected from your account's payment schedule")
 	}
 
 	// Warn about unusual analysis periods
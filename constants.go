@@ -19,6 +19,12 @@ const (
 
 	// OctopusRESTAPIBase is the base URL for REST API endpoints
 	OctopusRESTAPIBase = "https://api.octopus.energy/v1"
+
+	// OpenMeteoArchiveURL is the Open-Meteo historical weather API endpoint
+	OpenMeteoArchiveURL = "https://archive-api.open-meteo.com/v1/archive"
+
+	// GitHubReleasesURL is the GitHub API endpoint for the latest octobudget release
+	GitHubReleasesURL = "https://api.github.com/repos/matthewgall/octobudget/releases/latest"
 )
 
 // GraphQL query to obtain JWT token
@@ -54,6 +60,7 @@ query AccountDetails($accountNumber: String!) {
               displayName
               fullName
               standingCharge
+              tariffCode
             }
             ... on StandardTariff {
               unitRate
@@ -72,6 +79,7 @@ query AccountDetails($accountNumber: String!) {
         mprn
         meters {
           serialNumber
+          consumptionUnits
           smartDevices {
             deviceId
           }
@@ -84,6 +92,7 @@ query AccountDetails($accountNumber: String!) {
               displayName
               fullName
               standingCharge
+              tariffCode
             }
             ... on GasTariffType {
               unitRate
@@ -95,3 +104,89 @@ query AccountDetails($accountNumber: String!) {
   }
 }
 `
+
+// GraphQL query to fetch the account's most recent bills and statements
+const accountBillsQuery = `
+query AccountBills($accountNumber: String!, $first: Int!) {
+  account(accountNumber: $accountNumber) {
+    bills(first: $first) {
+      edges {
+        node {
+          id
+          billType
+          issuedDate
+          fromDate
+          toDate
+          ... on StatementType {
+            openingBalance
+            closingBalance
+            totalCharges {
+              grossTotal
+            }
+            totalCredits {
+              grossTotal
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// GraphQL query to fetch the account's most recent ledger transactions
+const accountTransactionsQuery = `
+query AccountTransactions($accountNumber: String!, $first: Int!) {
+  account(accountNumber: $accountNumber) {
+    transactions(first: $first) {
+      edges {
+        node {
+          __typename
+          id
+          postedDate
+          title
+          amounts {
+            gross
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// GraphQL query to fetch the account's active payment schedules
+const paymentSchedulesQuery = `
+query PaymentSchedules($accountNumber: String!) {
+  account(accountNumber: $accountNumber) {
+    paymentSchedules(first: 5, active: true) {
+      edges {
+        node {
+          id
+          validFrom
+          validTo
+          paymentAmount
+          paymentDay
+          paymentFrequency
+          paymentFrequencyMultiplier
+          isVariablePaymentAmount
+        }
+      }
+    }
+  }
+}
+`
+
+// GraphQL query to fetch recently completed Intelligent Octopus dispatches
+const completedDispatchesQuery = `
+query CompletedDispatches($accountNumber: String!) {
+  completedDispatches(accountNumber: $accountNumber) {
+    start
+    end
+    delta
+    meta {
+      source
+    }
+  }
+}
+`
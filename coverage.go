@@ -0,0 +1,302 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Data confidence levels, from how much of the analysis period the meters cover
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+const (
+	// coverageHighPercent is the coverage at or above which averages are trusted
+	coverageHighPercent = 90.0
+
+	// coverageLowPercent is the coverage below which insights are low confidence
+	coverageLowPercent = 60.0
+
+	// staleReadingAge is how old the last reading can be before it's reported
+	staleReadingAge = 48 * time.Hour
+
+	// maxReportedGaps limits the gaps kept per meter for the report
+	maxReportedGaps = 10
+
+	// defaultSlotLength is the interval smart meters report at
+	defaultSlotLength = 30 * time.Minute
+)
+
+// zeroRunSlots is how many consecutive zero readings count as a suspicious
+// run for each fuel. Export is zero every night, so it isn't checked.
+var zeroRunSlots = map[string]int{
+	"electricity": 12, // 6 hours - fridges and standby never stop
+	"gas":         96, // 2 days - hot water alone uses some gas most days
+}
+
+// AssessCoverage measures how completely a meter's readings cover the period
+// from start to end: the slots present, missing and duplicated, runs of zero
+// readings, and when the last reading arrived
+func AssessCoverage(fuelType string, consumptions []Consumption, start, end time.Time) DataCoverage {
+	coverage := DataCoverage{
+		FuelType:    fuelType,
+		PeriodStart: start,
+		PeriodEnd:   end,
+	}
+
+	slot := slotLength(consumptions)
+	coverage.SlotMinutes = int(slot / time.Minute)
+
+	// Index readings by slot, counting any repeats
+	readings := make(map[int64]float64, len(consumptions))
+	for _, c := range consumptions {
+		key := c.StartAt.Truncate(slot).Unix()
+		if _, seen := readings[key]; seen {
+			coverage.DuplicateSlots++
+			continue
+		}
+		readings[key] = c.Value
+
+		if coverage.FirstReading.IsZero() || c.StartAt.Before(coverage.FirstReading) {
+			coverage.FirstReading = c.StartAt
+		}
+		if c.EndAt.After(coverage.LastReading) {
+			coverage.LastReading = c.EndAt
+		}
+	}
+
+	// Walk every slot in the period, collecting runs of missing and zero readings
+	zeroLimit := zeroRunSlots[fuelType]
+	var missing, zeros *DataGap
+	closeRun := func(run **DataGap, minSlots int, record *[]DataGap) {
+		if *run != nil && (*run).Slots >= minSlots {
+			*record = append(*record, **run)
+		}
+		*run = nil
+	}
+
+	// Only whole slots inside the period are expected
+	first := start.Truncate(slot)
+	if first.Before(start) {
+		first = first.Add(slot)
+	}
+	for t := first; !t.Add(slot).After(end); t = t.Add(slot) {
+		coverage.ExpectedSlots++
+		value, present := readings[t.Unix()]
+
+		if !present {
+			coverage.MissingSlots++
+			if missing == nil {
+				missing = &DataGap{Start: t, Kind: GapMissing}
+			}
+			missing.End = t.Add(slot)
+			missing.Slots++
+			closeRun(&zeros, zeroLimit, &coverage.Gaps)
+			continue
+		}
+
+		coverage.PresentSlots++
+		closeRun(&missing, 1, &coverage.Gaps)
+
+		if zeroLimit > 0 && value == 0 {
+			coverage.ZeroSlots++
+			if zeros == nil {
+				zeros = &DataGap{Start: t, Kind: GapZero}
+			}
+			zeros.End = t.Add(slot)
+			zeros.Slots++
+		} else {
+			closeRun(&zeros, zeroLimit, &coverage.Gaps)
+		}
+	}
+	closeRun(&missing, 1, &coverage.Gaps)
+	closeRun(&zeros, zeroLimit, &coverage.Gaps)
+
+	// Readings outside the period still tell us when data last arrived, but
+	// only slots inside it count towards coverage
+	coverage.DaysCovered = float64(coverage.PresentSlots) * slot.Hours() / 24
+	if coverage.ExpectedSlots > 0 {
+		coverage.CoveragePercent = float64(coverage.PresentSlots) / float64(coverage.ExpectedSlots) * 100
+	}
+
+	// Keep the longest gaps, in date order
+	coverage.GapCount = len(coverage.Gaps)
+	if len(coverage.Gaps) > maxReportedGaps {
+		sort.SliceStable(coverage.Gaps, func(i, j int) bool {
+			return coverage.Gaps[i].Slots > coverage.Gaps[j].Slots
+		})
+		coverage.Gaps = coverage.Gaps[:maxReportedGaps]
+	}
+	sort.SliceStable(coverage.Gaps, func(i, j int) bool {
+		return coverage.Gaps[i].Start.Before(coverage.Gaps[j].Start)
+	})
+
+	return coverage
+}
+
+// slotLength returns the most common reading interval, defaulting to half an hour
+func slotLength(consumptions []Consumption) time.Duration {
+	counts := make(map[time.Duration]int)
+	best, bestCount := defaultSlotLength, 0
+	for _, c := range consumptions {
+		length := c.EndAt.Sub(c.StartAt)
+		if length <= 0 {
+			continue
+		}
+		counts[length]++
+		if counts[length] > bestCount {
+			best, bestCount = length, counts[length]
+		}
+	}
+	return best
+}
+
+// Confidence rates how far the coverage can be trusted
+func (c DataCoverage) Confidence() string {
+	switch {
+	case c.CoveragePercent >= coverageHighPercent:
+		return ConfidenceHigh
+	case c.CoveragePercent >= coverageLowPercent:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
+	}
+}
+
+// Stale reports whether the last reading is old enough to suggest the meter
+// has stopped sending data
+func (c DataCoverage) Stale() bool {
+	return c.LastReading.IsZero() || c.PeriodEnd.Sub(c.LastReading) > staleReadingAge
+}
+
+// Label names the meter the coverage describes, including its property when
+// the result covers several
+func (c DataCoverage) Label() string {
+	label := fuelLabel(c.FuelType)
+	if c.Property != "" {
+		label += " (" + c.Property + ")"
+	}
+	return label
+}
+
+// Describe summarises a gap for the report
+func (g DataGap) Describe() string {
+	what := "No readings"
+	if g.Kind == GapZero {
+		what = "Zero readings"
+	}
+	return fmt.Sprintf("%s from %s to %s (%s)",
+		what,
+		g.Start.In(london).Format("2006-01-02 15:04"),
+		g.End.In(london).Format("2006-01-02 15:04"),
+		formatGapLength(g.End.Sub(g.Start)),
+	)
+}
+
+// formatGapLength formats a gap as hours or days
+func formatGapLength(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%.1f hours", d.Hours())
+	}
+	return fmt.Sprintf("%.1f days", d.Hours()/24)
+}
+
+// formatLastReading shows when a meter's most recent reading ended, flagging
+// readings that have stopped
+func formatLastReading(c DataCoverage) string {
+	if c.LastReading.IsZero() {
+		return "None"
+	}
+	last := c.LastReading.In(london).Format("2006-01-02 15:04")
+	if c.Stale() {
+		last += " ⚠️"
+	}
+	return last
+}
+
+// confidenceLabel capitalises a confidence level for display
+func confidenceLabel(confidence string) string {
+	switch confidence {
+	case ConfidenceHigh:
+		return "High"
+	case ConfidenceMedium:
+		return "Medium"
+	case ConfidenceLow:
+		return "Low"
+	}
+	return "Unknown"
+}
+
+// fuelLabel capitalises a fuel type for display
+func fuelLabel(fuelType string) string {
+	switch fuelType {
+	case "electricity":
+		return "Electricity"
+	case "export":
+		return "Export"
+	case "gas":
+		return "Gas"
+	}
+	return fuelType
+}
+
+// overallConfidence is the lowest confidence across the meters that were assessed
+func overallConfidence(coverage []DataCoverage) string {
+	confidence := ConfidenceHigh
+	for _, c := range coverage {
+		switch c.Confidence() {
+		case ConfidenceLow:
+			return ConfidenceLow
+		case ConfidenceMedium:
+			confidence = ConfidenceMedium
+		}
+	}
+	return confidence
+}
+
+// dedupeConsumption keeps the first reading for each slot
+func dedupeConsumption(consumptions []Consumption, slot time.Duration) []Consumption {
+	if slot <= 0 {
+		slot = defaultSlotLength
+	}
+
+	seen := make(map[int64]bool, len(consumptions))
+	deduped := make([]Consumption, 0, len(consumptions))
+	for _, c := range consumptions {
+		key := c.StartAt.Truncate(slot).Unix()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, c)
+	}
+	return deduped
+}
+
+// averageOverCoverage averages a period total over the days the readings
+// actually cover, falling back to the full period when nothing was assessed
+func averageOverCoverage(total, daysCovered float64, periodDays int) float64 {
+	if daysCovered > 0 {
+		return total / daysCovered
+	}
+	if periodDays > 0 {
+		return total / float64(periodDays)
+	}
+	return 0
+}
@@ -15,6 +15,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 )
 
 // APIError represents an API-related error
@@ -22,6 +23,7 @@ type APIError struct {
 	StatusCode int
 	Endpoint   string
 	Message    string
+	RetryAfter time.Duration // Server-requested delay before retrying, if any
 	Err        error
 }
 
@@ -38,6 +40,10 @@ func (e *APIError) Unwrap() error {
 
 // IsRetryable returns true if this error should be retried
 func (e *APIError) IsRetryable() bool {
+	// Transport failures (timeouts, resets) carry no status code and are transient
+	if e.StatusCode == 0 && e.Err != nil {
+		return true
+	}
 	return isRetryableStatus(e.StatusCode)
 }
 
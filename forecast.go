@@ -0,0 +1,321 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// forecastMonths is how far ahead the balance is projected
+	forecastMonths = 12
+
+	// minMonthsToAnniversary is the shortest run-up to an anniversary the
+	// recommendation aims at; closer than this it aims at the one after
+	minMonthsToAnniversary = 3
+
+	// directDebitStep rounds recommendations up to a practical amount (pounds)
+	directDebitStep = 5.0
+)
+
+// typicalMonthlyDegreeDays spreads a typical UK year's heating degree-days
+// (at heatingBaseTemp) across the months, January first
+var typicalMonthlyDegreeDays = [12]float64{315, 280, 255, 185, 115, 55, 25, 30, 70, 145, 235, 290}
+
+// forecastSource is one property's daily usage and prices, projected forward
+type forecastSource struct {
+	result *AnalysisResult
+}
+
+// forecastSources returns each property of a rollup, or the result itself,
+// since pooled readings from several homes can't share one heating model
+func forecastSources(result *AnalysisResult) []forecastSource {
+	if len(result.Properties) == 0 {
+		return []forecastSource{{result: result}}
+	}
+	sources := make([]forecastSource, 0, len(result.Properties))
+	for _, property := range result.Properties {
+		sources = append(sources, forecastSource{result: property})
+	}
+	return sources
+}
+
+// dailyUsage returns a fuel's expected kWh for a day in a month with typical
+// weather, from its degree-day model when it has one
+func (s forecastSource) dailyUsage(fuelType string, day time.Time, average float64) float64 {
+	model, ok := heatingModel(s.result.HeatingModels, fuelType)
+	if !ok {
+		return average
+	}
+
+	degreeDays := typicalMonthlyDegreeDays[day.In(london).Month()-1] / float64(daysInMonth(day))
+	return math.Max(0, model.BaseloadKWh+model.KWhPerDegreeDay*degreeDays)
+}
+
+// dailyCost prices a forecast day in pounds with the agreement in force that
+// day, falling back to the rates actually paid over the analysis period
+func (s forecastSource) dailyCost(day time.Time) (float64, float64, float64) {
+	r := s.result
+	electricity := s.dailyUsage("electricity", day, r.AvgDailyElectricity)
+	gas := s.dailyUsage("gas", day, r.AvgDailyGas)
+
+	cost := electricity*s.electricityRate(day)/100 + gas*s.gasRate(day)/100
+	cost += standingCharge(day, r.ElectricityAgreements, r.AvgDailyStandingElectricity)
+	cost += standingCharge(day, r.GasAgreements, r.AvgDailyStandingGas)
+	cost -= r.AvgDailyEarningsExport
+
+	return cost, electricity, gas
+}
+
+// electricityRate is the unit rate (p/kWh) for a day, weighting time-of-use
+// bands by the share of import each took over the analysis period
+func (s forecastSource) electricityRate(day time.Time) float64 {
+	r := s.result
+	tariff := forecastTariff(day, r.ElectricityAgreements)
+	if tariff != nil && r.LoadProfile != nil {
+		var rate, share float64
+		for _, usage := range r.LoadProfile.Bands {
+			rate += tariff.RateForBand(usage.Band) * usage.SharePercent / 100
+			share += usage.SharePercent / 100
+		}
+		if rate > 0 && share > 0 {
+			return rate / share
+		}
+	}
+	if tariff != nil && tariff.UnitRate > 0 {
+		return tariff.UnitRate
+	}
+	return paidRate(r.AvgDailyCostElectricity, r.AvgDailyElectricity)
+}
+
+// gasRate is the gas unit rate (p/kWh) for a day
+func (s forecastSource) gasRate(day time.Time) float64 {
+	r := s.result
+	if tariff := forecastTariff(day, r.GasAgreements); tariff != nil && tariff.UnitRate > 0 {
+		return tariff.UnitRate
+	}
+	return paidRate(r.AvgDailyCostGas, r.AvgDailyGas)
+}
+
+// forecastTariff returns the tariff agreed for a day, or the latest known one
+func forecastTariff(day time.Time, agreements []Agreement) *Tariff {
+	if tariff := findActiveTariff(day, agreements); tariff != nil {
+		return tariff
+	}
+	return currentTariff(agreements)
+}
+
+// standingCharge returns a day's standing charge in pounds, falling back to the
+// average charged over the analysis period
+func standingCharge(day time.Time, agreements []Agreement, average float64) float64 {
+	if tariff := forecastTariff(day, agreements); tariff != nil && tariff.StandingCharge > 0 {
+		return tariff.StandingCharge / 100
+	}
+	return average
+}
+
+// paidRate is the average unit rate (p/kWh) paid, from daily cost and usage
+func paidRate(dailyCost, dailyKWh float64) float64 {
+	if dailyKWh <= 0 {
+		return 0
+	}
+	return dailyCost * 100 / dailyKWh
+}
+
+// nextAnniversary returns the first anniversary of start at least
+// minMonthsToAnniversary after from
+func nextAnniversary(start, from time.Time) time.Time {
+	local := start.In(london)
+	earliest := from.AddDate(0, minMonthsToAnniversary, 0)
+	for year := from.In(london).Year(); ; year++ {
+		anniversary := time.Date(year, local.Month(), local.Day(), 0, 0, 0, 0, london)
+		if !anniversary.Before(earliest) {
+			return anniversary
+		}
+	}
+}
+
+// accountStart is when supply began: the configured anniversary, or the
+// earliest agreement on the account
+func (a *Analyzer) accountStart(result *AnalysisResult) (time.Time, string) {
+	if a.config.AccountAnniversary != "" {
+		if start, err := time.ParseInLocation("2006-01-02", a.config.AccountAnniversary, london); err == nil {
+			return start, "config"
+		}
+	}
+
+	var earliest time.Time
+	sources := append([]*AnalysisResult{result}, result.Properties...)
+	for _, source := range sources {
+		for _, agreements := range [][]Agreement{source.ElectricityAgreements, source.GasAgreements} {
+			for _, agreement := range agreements {
+				if earliest.IsZero() || agreement.ValidFrom.Before(earliest) {
+					earliest = agreement.ValidFrom
+				}
+			}
+		}
+	}
+	if !earliest.IsZero() {
+		return earliest, "agreements"
+	}
+	return time.Time{}, ""
+}
+
+// forecastPaymentDay is the day of the month collections are taken
+func forecastPaymentDay(schedule *PaymentSchedule) int {
+	if schedule != nil && schedule.PaymentDay > 0 {
+		return schedule.PaymentDay
+	}
+	return 1
+}
+
+// isPaymentDay reports whether a Direct Debit is collected on day, clamping
+// the payment day to the length of the month
+func isPaymentDay(day time.Time, paymentDay int) bool {
+	if last := daysInMonth(day); paymentDay > last {
+		paymentDay = last
+	}
+	return day.In(london).Day() == paymentDay
+}
+
+// forecastDirectDebit projects usage, cost and balance month by month and
+// recommends the Direct Debit that reaches the target balance at the account
+// anniversary
+func (a *Analyzer) forecastDirectDebit(result *AnalysisResult) *BalanceForecast {
+	sources := forecastSources(result)
+	today := londonDayStart(now())
+	forecast := &BalanceForecast{
+		StartBalance:  result.CurrentBalance,
+		TargetBalance: a.config.DirectDebitTargetBalance,
+		PaymentDay:    forecastPaymentDay(result.PaymentSchedule),
+	}
+
+	// Aim at the anniversary, or a year ahead when it isn't known
+	start, source := a.accountStart(result)
+	if start.IsZero() {
+		forecast.TargetDate = today.AddDate(1, 0, 0)
+	} else {
+		forecast.TargetDate = nextAnniversary(start, today)
+		forecast.AnniversarySource = source
+	}
+
+	end := today.AddDate(0, forecastMonths, 0)
+	if forecast.TargetDate.After(end) {
+		end = forecast.TargetDate
+	}
+
+	// Cost every day up to the horizon, noting what falls before the anniversary
+	type forecastDay struct {
+		date    time.Time
+		cost    float64
+		payment bool
+	}
+	var days []forecastDay
+	var costToTarget float64
+	payments := 0
+	for day := today; day.Before(end); day = day.AddDate(0, 0, 1) {
+		var cost, electricity, gas float64
+		for _, s := range sources {
+			c, e, g := s.dailyCost(day)
+			cost, electricity, gas = cost+c, electricity+e, gas+g
+		}
+
+		payment := day.After(today) && isPaymentDay(day, forecast.PaymentDay)
+		days = append(days, forecastDay{date: day, cost: cost, payment: payment})
+		if day.Before(forecast.TargetDate) {
+			costToTarget += cost
+			if payment {
+				payments++
+			}
+		}
+
+		// Group by calendar month for reports
+		month := monthStart(day)
+		if n := len(forecast.Months); n == 0 || !forecast.Months[n-1].Month.Equal(month) {
+			forecast.Months = append(forecast.Months, ForecastMonth{Month: month})
+		}
+		m := &forecast.Months[len(forecast.Months)-1]
+		m.Days++
+		m.DegreeDays += typicalMonthlyDegreeDays[month.Month()-1] / float64(daysInMonth(month))
+		m.ElectricityKWh += electricity
+		m.GasKWh += gas
+		m.Cost += cost
+	}
+	forecast.CostToTarget = costToTarget
+	forecast.Payments = payments
+
+	// The amount that leaves the target balance once the anniversary is reached
+	if payments > 0 {
+		needed := (forecast.TargetBalance - forecast.StartBalance + costToTarget) / float64(payments)
+		forecast.RecommendedDirectDebit = math.Max(0, math.Ceil(needed/directDebitStep)*directDebitStep)
+	}
+
+	// Walk the balance forward under the current and recommended payments
+	current, recommended := result.CurrentDirectDebit, forecast.RecommendedDirectDebit
+	balanceCurrent, balanceRecommended := forecast.StartBalance, forecast.StartBalance
+	month := 0
+	for _, day := range days {
+		for !forecast.Months[month].Month.Equal(monthStart(day.date)) {
+			month++
+		}
+		balanceCurrent -= day.cost
+		balanceRecommended -= day.cost
+		if day.payment {
+			balanceCurrent += current
+			balanceRecommended += recommended
+		}
+		forecast.Months[month].BalanceCurrent = balanceCurrent
+		forecast.Months[month].BalanceRecommended = balanceRecommended
+		if day.date.Equal(forecast.TargetDate.AddDate(0, 0, -1)) {
+			forecast.TargetBalanceCurrent = balanceCurrent
+		}
+	}
+
+	return forecast
+}
+
+// forecastBasis explains what the forecast was built from, for reports
+func forecastBasis(result *AnalysisResult) []string {
+	forecast := result.Forecast
+	var basis []string
+
+	var modelled []string
+	for _, source := range forecastSources(result) {
+		for _, model := range source.result.HeatingModels {
+			label := strings.ToLower(fuelLabel(model.FuelType))
+			if len(result.Properties) > 0 {
+				label = fmt.Sprintf("%s at %s", label, source.result.PropertyID)
+			}
+			modelled = append(modelled, label)
+		}
+	}
+	if len(modelled) > 0 {
+		basis = append(basis, fmt.Sprintf("Weather-normalised usage for %s, with typical monthly degree-days", strings.Join(modelled, ", ")))
+	}
+	basis = append(basis, fmt.Sprintf("Average daily usage over the last %d days for anything that doesn't follow the weather", result.AnalysisPeriodDays))
+	basis = append(basis, "Current and upcoming tariff agreements, including standing charges")
+	basis = append(basis, fmt.Sprintf("Your current balance of %s", FormatCurrency(forecast.StartBalance)))
+
+	anniversary := forecast.TargetDate.In(london).Format("2 January 2006")
+	switch forecast.AnniversarySource {
+	case "":
+		basis = append(basis, fmt.Sprintf("Reaching a balance of %s on %s (account anniversary unknown)", FormatCurrency(forecast.TargetBalance), anniversary))
+	default:
+		basis = append(basis, fmt.Sprintf("Reaching a balance of %s at your account anniversary on %s (%d payments)", FormatCurrency(forecast.TargetBalance), anniversary, forecast.Payments))
+	}
+	return basis
+}
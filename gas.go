@@ -0,0 +1,208 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Gas meter units. SMETS2 meters report volume in cubic metres, SMETS1 meters
+// report energy in kWh.
+const (
+	GasUnitCubicMetres = "m³"
+	GasUnitKWh         = "kWh"
+
+	// GasUnitMixed marks a meter point whose serials report in different units,
+	// such as a SMETS1 meter exchanged for a SMETS2 one
+	GasUnitMixed = "mixed"
+)
+
+// Gas unit sources, recorded so reports can explain where the unit came from
+const (
+	GasUnitSourceConfig  = "config"
+	GasUnitSourceMeter   = "meter details"
+	GasUnitSourceDefault = "default"
+)
+
+const (
+	// GasVolumeCorrectionFactor adjusts metered volume for temperature and pressure
+	GasVolumeCorrectionFactor = 1.02264
+
+	// DefaultGasCalorificValue is a typical GB calorific value in MJ/m³
+	DefaultGasCalorificValue = 39.5
+
+	// megajoulesPerKWh converts MJ to kWh
+	megajoulesPerKWh = 3.6
+)
+
+// CalorificValueConfig is a calorific value that applies from a date onwards
+type CalorificValueConfig struct {
+	From  string  `yaml:"from"`  // YYYY-MM-DD
+	Value float64 `yaml:"value"` // MJ/m³
+}
+
+// calorificPeriod is a parsed calorific value and the day it starts applying
+type calorificPeriod struct {
+	from  time.Time
+	value float64
+}
+
+// CalorificValues looks up the calorific value in force on a given day
+type CalorificValues struct {
+	fallback float64
+	periods  []calorificPeriod // Oldest first
+}
+
+// NewCalorificValues builds a lookup from the configured default and any dated
+// values. Days before the first dated value use the default.
+func NewCalorificValues(fallback float64, dated []CalorificValueConfig) (*CalorificValues, error) {
+	if fallback <= 0 {
+		fallback = DefaultGasCalorificValue
+	}
+
+	values := &CalorificValues{fallback: fallback}
+	for i, entry := range dated {
+		from, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(entry.From), london)
+		if err != nil {
+			return nil, fmt.Errorf("gas_calorific_values[%d]: from must be a date (YYYY-MM-DD)", i)
+		}
+		if !isPlausibleCalorificValue(entry.Value) {
+			return nil, fmt.Errorf("gas_calorific_values[%d]: value must be between 30 and 50 MJ/m³", i)
+		}
+		values.periods = append(values.periods, calorificPeriod{from: from, value: entry.Value})
+	}
+
+	sort.Slice(values.periods, func(i, j int) bool {
+		return values.periods[i].from.Before(values.periods[j].from)
+	})
+
+	return values, nil
+}
+
+// At returns the calorific value (MJ/m³) in force at t
+func (v *CalorificValues) At(t time.Time) float64 {
+	value := v.fallback
+	for _, period := range v.periods {
+		if t.Before(period.from) {
+			break
+		}
+		value = period.value
+	}
+	return value
+}
+
+// isPlausibleCalorificValue reports whether a calorific value is within the
+// range seen on the GB gas network
+func isPlausibleCalorificValue(value float64) bool {
+	return value >= 30 && value <= 50
+}
+
+// GasVolumeToKWh converts a metered gas volume in m³ to kWh
+func GasVolumeToKWh(cubicMetres, calorificValue float64) float64 {
+	return cubicMetres * GasVolumeCorrectionFactor * calorificValue / megajoulesPerKWh
+}
+
+// normalizeGasUnit maps the spellings used by the API and config to a gas unit,
+// returning "" when the unit isn't recognised
+func normalizeGasUnit(unit string) string {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "m3", "m³", "m^3", "cubic metres", "cubic meters":
+		return GasUnitCubicMetres
+	case "kwh":
+		return GasUnitKWh
+	}
+	return ""
+}
+
+// ResolveGasUnit determines the unit a gas meter reports in, preferring the
+// configured override, then the meter's own details. Meters that don't say are
+// assumed to report kWh.
+func ResolveGasUnit(override string, meter *Meter) (string, string) {
+	if unit := normalizeGasUnit(override); unit != "" {
+		return unit, GasUnitSourceConfig
+	}
+
+	if meter != nil {
+		if unit := normalizeGasUnit(meter.ConsumptionUnits); unit != "" {
+			return unit, GasUnitSourceMeter
+		}
+	}
+
+	return GasUnitKWh, GasUnitSourceDefault
+}
+
+// ConvertGasToKWh converts gas readings in m³ to kWh using the calorific value
+// for each reading's day, keeping the original reading in RawValue
+func ConvertGasToKWh(consumptions []Consumption, calorificValues *CalorificValues) []Consumption {
+	converted := make([]Consumption, len(consumptions))
+	for i, c := range consumptions {
+		c.RawValue = c.Value
+		c.Value = GasVolumeToKWh(c.Value, calorificValues.At(c.StartAt))
+		converted[i] = c
+	}
+	return converted
+}
+
+// summarizeGasVolume totals the metered volume of converted gas readings and
+// works out the average calorific value the conversion used. Readings metered
+// in kWh have no volume and are left out.
+func summarizeGasVolume(consumptions []Consumption) (float64, float64) {
+	var volume, energy float64
+	for _, c := range consumptions {
+		if c.RawValue <= 0 {
+			continue
+		}
+		volume += c.RawValue
+		energy += c.Value
+	}
+	if volume == 0 {
+		return 0, 0
+	}
+	return volume, energy * megajoulesPerKWh / (volume * GasVolumeCorrectionFactor)
+}
+
+// gasConversionNote explains how a result's gas readings were converted to kWh
+func gasConversionNote(result *AnalysisResult) string {
+	metered := "Gas meter reports m³"
+	if result.GasUnit == GasUnitMixed {
+		metered = "Gas was metered in m³ for part of the period, and the metered volume covers those days only"
+	}
+	return fmt.Sprintf("%s (unit from %s), converted to kWh using a volume correction factor of %.5f and an average calorific value of %.1f MJ/m³.",
+		metered, result.GasUnitSource, GasVolumeCorrectionFactor, result.GasCalorificValue)
+}
+
+// meteredVolumeDays is the number of days of readings supplied by gas serials
+// that report m³
+func meteredVolumeDays(segments []MeterSegment) float64 {
+	var days float64
+	for _, segment := range segments {
+		if segment.FuelType == "gas" && segment.Unit == GasUnitCubicMetres {
+			days += float64(segment.Readings) * defaultSlotLength.Hours() / 24
+		}
+	}
+	return days
+}
+
+// findMeter returns the meter with the given serial number, if listed
+func findMeter(meters []Meter, serialNumber string) *Meter {
+	for i := range meters {
+		if meters[i].SerialNumber == serialNumber {
+			return &meters[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// heatingBaseTemp is the mean daily temperature (°C) below which homes need heating
+	heatingBaseTemp = 15.5
+
+	// typicalAnnualDegreeDays is the heating degree-days in a typical UK year
+	// at heatingBaseTemp, used to put usage on a like-for-like footing
+	typicalAnnualDegreeDays = 2000.0
+
+	// minWeatherDays is the fewest days with weather a degree-day fit needs
+	minWeatherDays = 14
+
+	// minElectricHeatingFit is the R² above which electricity is treated as
+	// heating the home, rather than happening to vary with the seasons
+	minElectricHeatingFit = 0.4
+
+	// heatingChangePercent is the weather-normalised change worth an insight
+	heatingChangePercent = 10.0
+)
+
+// degreeDayFit is a straight-line fit of daily usage against heating degree-days
+type degreeDayFit struct {
+	baseload  float64 // kWh per day with no heating
+	perDegree float64 // kWh per heating degree-day
+	scale     float64 // Spread of the residuals
+	rSquared  float64 // Share of the day-to-day variation the weather explains
+	days      int
+}
+
+// fitDegreeDays fits daily usage against heating degree-days for days with
+// weather. It returns nil when there's too little weather or usage doesn't
+// rise as it gets colder, in which case the weather doesn't explain usage.
+func fitDegreeDays(days []Consumption, weather map[string]*WeatherData) *degreeDayFit {
+	var xs, ys []float64
+	for _, day := range days {
+		if w, ok := weather[londonDate(day.StartAt)]; ok {
+			xs = append(xs, heatingDegreeDays(w.TempMean))
+			ys = append(ys, day.Value)
+		}
+	}
+	if len(xs) < minWeatherDays {
+		return nil
+	}
+
+	intercept, slope, ok := linearFit(xs, ys)
+	if !ok || slope <= 0 {
+		return nil
+	}
+
+	meanY := calculateMean(ys)
+	var residualSquares, totalSquares float64
+	residuals := make([]float64, len(xs))
+	for i := range xs {
+		residual := ys[i] - (intercept + slope*xs[i])
+		residuals[i] = math.Abs(residual)
+		residualSquares += residual * residual
+		totalSquares += (ys[i] - meanY) * (ys[i] - meanY)
+	}
+
+	fit := &degreeDayFit{
+		baseload:  intercept,
+		perDegree: slope,
+		scale:     madScale * calculateMedian(residuals),
+		days:      len(xs),
+	}
+	if totalSquares > 0 {
+		fit.rSquared = 1 - residualSquares/totalSquares
+	}
+	return fit
+}
+
+// expected returns the usage the fit predicts for a day's mean temperature
+func (f *degreeDayFit) expected(tempMean float64) float64 {
+	return math.Max(0, f.baseload+f.perDegree*heatingDegreeDays(tempMean))
+}
+
+// analyzeHeating fits gas, and electricity where it heats the home, against
+// the period's weather, and compares the two halves of the period on equal
+// weather so changes such as new insulation show up
+func (a *Analyzer) analyzeHeating(result *AnalysisResult, data *CollectedData, weather map[string]*WeatherData) {
+	if len(weather) == 0 {
+		return
+	}
+
+	fuels := []struct {
+		fuelType     string
+		consumptions []Consumption
+	}{
+		{"gas", data.GasConsumption},
+		{"electricity", data.ElectricityConsumption},
+	}
+
+	for _, fuel := range fuels {
+		days := completeDays(fuel.consumptions)
+		fit := fitDegreeDays(days, weather)
+		if fit == nil {
+			continue
+		}
+		if fuel.fuelType == "electricity" && fit.rSquared < minElectricHeatingFit {
+			continue
+		}
+
+		model := HeatingModel{
+			FuelType:            fuel.fuelType,
+			Days:                fit.days,
+			BaseTemp:            heatingBaseTemp,
+			BaseloadKWh:         fit.baseload,
+			KWhPerDegreeDay:     fit.perDegree,
+			RSquared:            fit.rSquared,
+			NormalisedAnnualKWh: math.Max(0, fit.baseload)*365 + fit.perDegree*typicalAnnualDegreeDays,
+		}
+
+		var total float64
+		for _, day := range days {
+			if w, ok := weather[londonDate(day.StartAt)]; ok {
+				model.DegreeDays += heatingDegreeDays(w.TempMean)
+				total += day.Value
+			}
+		}
+		if total > 0 {
+			model.HeatingSharePercent = math.Min(100, fit.perDegree*model.DegreeDays/total*100)
+		}
+
+		model.Comparison = compareHalves(days, weather)
+		result.HeatingModels = append(result.HeatingModels, model)
+
+		a.logger.Info("Fitted heating model",
+			"fuel", fuel.fuelType,
+			"baseload_kwh", fit.baseload,
+			"kwh_per_degree_day", fit.perDegree,
+			"r_squared", fit.rSquared,
+		)
+	}
+}
+
+// compareHalves fits the earlier half of the period and predicts the recent
+// half from its weather, so the difference is what changed beyond the weather
+func compareHalves(days []Consumption, weather map[string]*WeatherData) *WeatherComparison {
+	if len(days) < 2*minWeatherDays {
+		return nil
+	}
+
+	mid := len(days) / 2
+	earlier, recent := days[:mid], days[mid:]
+	earlierFit := fitDegreeDays(earlier, weather)
+	if earlierFit == nil {
+		return nil
+	}
+
+	comparison := &WeatherComparison{
+		EarlierStart:           earlier[0].StartAt,
+		EarlierEnd:             earlier[len(earlier)-1].EndAt,
+		RecentStart:            recent[0].StartAt,
+		RecentEnd:              recent[len(recent)-1].EndAt,
+		EarlierKWhPerDegreeDay: earlierFit.perDegree,
+	}
+	if recentFit := fitDegreeDays(recent, weather); recentFit != nil {
+		comparison.RecentKWhPerDegreeDay = recentFit.perDegree
+	}
+
+	for _, day := range recent {
+		if w, ok := weather[londonDate(day.StartAt)]; ok {
+			comparison.RecentActualKWh += day.Value
+			comparison.RecentExpectedKWh += earlierFit.expected(w.TempMean)
+		}
+	}
+	if comparison.RecentExpectedKWh <= 0 {
+		return nil
+	}
+
+	comparison.ChangePercent = (comparison.RecentActualKWh - comparison.RecentExpectedKWh) / comparison.RecentExpectedKWh * 100
+	return comparison
+}
+
+// heatingModel returns the fitted model for a fuel, if the weather explains its usage
+func heatingModel(models []HeatingModel, fuelType string) (HeatingModel, bool) {
+	for _, model := range models {
+		if model.FuelType == fuelType {
+			return model, true
+		}
+	}
+	return HeatingModel{}, false
+}
+
+// ExpectedKWh returns the daily usage the model predicts for a mean temperature
+func (m HeatingModel) ExpectedKWh(tempMean float64) float64 {
+	return math.Max(0, m.BaseloadKWh+m.KWhPerDegreeDay*heatingDegreeDays(tempMean))
+}
+
+// heatingInsights reports weather-normalised changes in heating use between
+// the two halves of the period
+func heatingInsights(result *AnalysisResult) []Insight {
+	var insights []Insight
+	for _, model := range result.HeatingModels {
+		comparison := model.Comparison
+		if comparison == nil || math.Abs(comparison.ChangePercent) < heatingChangePercent {
+			continue
+		}
+
+		fuel := fuelLabel(model.FuelType)
+		since := comparison.RecentStart.In(london).Format("2 January")
+		if comparison.ChangePercent < 0 {
+			insights = append(insights, Insight{
+				Category:    "heating",
+				Priority:    "low",
+				Title:       fmt.Sprintf("%s Use Down After Allowing for Weather", fuel),
+				Description: fmt.Sprintf("Since %s you've used %.0f kWh of %s, %.0f%% less than your earlier usage predicts for the same weather (%.0f kWh). Changes such as insulation or a lower thermostat are paying off.", since, comparison.RecentActualKWh, model.FuelType, -comparison.ChangePercent, comparison.RecentExpectedKWh),
+				Action:      "No action needed - keep monitoring to confirm the saving holds through colder weather",
+			})
+			continue
+		}
+
+		insights = append(insights, Insight{
+			Category:    "heating",
+			Priority:    "medium",
+			Title:       fmt.Sprintf("%s Use Up After Allowing for Weather", fuel),
+			Description: fmt.Sprintf("Since %s you've used %.0f kWh of %s, %.0f%% more than your earlier usage predicts for the same weather (%.0f kWh).", since, comparison.RecentActualKWh, model.FuelType, comparison.ChangePercent, comparison.RecentExpectedKWh),
+			Action:      "Check thermostat and heating schedules, and have the boiler or heat pump serviced if nothing has changed at home",
+		})
+	}
+	return insights
+}
+
+// heatingDegreeDays is how far a day's mean temperature fell below the heating base
+func heatingDegreeDays(tempMean float64) float64 {
+	return math.Max(0, heatingBaseTemp-tempMean)
+}
+
+// linearFit returns the least-squares intercept and slope of ys against xs
+func linearFit(xs, ys []float64) (float64, float64, bool) {
+	meanX, meanY := calculateMean(xs), calculateMean(ys)
+
+	var covariance, variance float64
+	for i := range xs {
+		covariance += (xs[i] - meanX) * (ys[i] - meanY)
+		variance += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if variance == 0 {
+		return 0, 0, false
+	}
+
+	slope := covariance / variance
+	return meanY - slope*meanX, slope, true
+}
+
+// formatComparisonRange formats one half of a weather comparison for reports
+func formatComparisonRange(start, end time.Time) string {
+	return fmt.Sprintf("%s to %s",
+		start.In(london).Format("2 Jan"),
+		end.Add(-time.Nanosecond).In(london).Format("2 Jan 2006"),
+	)
+}
+
+// describeComparison summarises a weather-normalised comparison for reports
+func describeComparison(model HeatingModel) string {
+	c := model.Comparison
+	direction := "more"
+	if c.ChangePercent < 0 {
+		direction = "less"
+	}
+
+	summary := fmt.Sprintf("%s: %s used %.0f kWh. Usage from %s predicts %.0f kWh for the same weather, so that's %.1f%% %s.",
+		fuelLabel(model.FuelType),
+		formatComparisonRange(c.RecentStart, c.RecentEnd),
+		c.RecentActualKWh,
+		formatComparisonRange(c.EarlierStart, c.EarlierEnd),
+		c.RecentExpectedKWh,
+		math.Abs(c.ChangePercent),
+		direction,
+	)
+	if c.RecentKWhPerDegreeDay > 0 {
+		summary += fmt.Sprintf(" Heating use went from %.2f to %.2f kWh per degree-day.", c.EarlierKWhPerDegreeDay, c.RecentKWhPerDegreeDay)
+	}
+	return summary
+}
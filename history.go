@@ -0,0 +1,269 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// monthAgoTolerance and yearAgoTolerance are how far a stored analysis may
+	// be from a month or a year before this one and still stand in for it
+	monthAgoTolerance = 14 * 24 * time.Hour
+	yearAgoTolerance  = 31 * 24 * time.Hour
+
+	// maxSparklinePoints is how many recent analyses the Markdown trend shows
+	maxSparklinePoints = 30
+)
+
+// sparkBlocks draw a sparkline from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// trendKind groups metrics that share a unit, and so a chart
+type trendKind int
+
+const (
+	trendUsage trendKind = iota
+	trendCost
+	trendPayment
+)
+
+// trendMetric is a headline figure tracked across analyses
+type trendMetric struct {
+	label  string
+	kind   trendKind
+	value  func(TrendSnapshot) float64
+	format func(float64) string
+}
+
+// trendMetrics lists the figures compared between analyses, in report order
+var trendMetrics = []trendMetric{
+	{"Electricity Import", trendUsage, func(s TrendSnapshot) float64 { return s.AvgDailyElectricity }, formatDailyKWh},
+	{"Gas", trendUsage, func(s TrendSnapshot) float64 { return s.AvgDailyGas }, formatDailyKWh},
+	{"Export", trendUsage, func(s TrendSnapshot) float64 { return s.AvgDailyExport }, formatDailyKWh},
+	{"Net Daily Cost", trendCost, func(s TrendSnapshot) float64 { return s.AvgDailyCostTotal }, formatDailyPounds},
+	{"Export Earnings", trendCost, func(s TrendSnapshot) float64 { return s.AvgDailyEarningsExport }, formatDailyPounds},
+	{"Recommended Direct Debit", trendPayment, func(s TrendSnapshot) float64 { return s.RecommendedDirectDebit }, formatMonthlyPounds},
+}
+
+// snapshotOf takes the headline figures from an analysis
+func snapshotOf(result *AnalysisResult) TrendSnapshot {
+	return TrendSnapshot{
+		GeneratedAt:            result.GeneratedAt,
+		AnalysisPeriodStart:    result.AnalysisPeriodStart,
+		AnalysisPeriodEnd:      result.AnalysisPeriodEnd,
+		AvgDailyElectricity:    result.AvgDailyElectricity,
+		AvgDailyGas:            result.AvgDailyGas,
+		AvgDailyExport:         result.AvgDailyExport,
+		AvgDailyCostTotal:      result.AvgDailyCostTotal,
+		AvgDailyEarningsExport: result.AvgDailyEarningsExport,
+		RecommendedDirectDebit: result.RecommendedDirectDebit,
+	}
+}
+
+// BuildTrendReport compares an analysis with the stored ones before it: the
+// last report, the one nearest a month earlier and the one nearest the same
+// time last year. It returns nil when there's nothing earlier to compare.
+func BuildTrendReport(result *AnalysisResult, stored []TrendSnapshot) *TrendReport {
+	current := snapshotOf(result)
+
+	var earlier []TrendSnapshot
+	for _, snapshot := range stored {
+		if snapshot.GeneratedAt.Before(current.GeneratedAt) {
+			earlier = append(earlier, snapshot)
+		}
+	}
+	if len(earlier) == 0 {
+		return nil
+	}
+
+	report := &TrendReport{
+		Runs:    len(earlier),
+		Current: current,
+	}
+
+	last := earlier[len(earlier)-1]
+	report.Comparisons = append(report.Comparisons, TrendComparison{Label: "Last report", Previous: last})
+
+	// A monthly run's last report is also last month's, so only add it once
+	if monthAgo, ok := nearestSnapshot(earlier, current.GeneratedAt.AddDate(0, -1, 0), monthAgoTolerance); ok && !monthAgo.GeneratedAt.Equal(last.GeneratedAt) {
+		report.Comparisons = append(report.Comparisons, TrendComparison{Label: "Last month", Previous: monthAgo})
+	}
+	if yearAgo, ok := nearestSnapshot(earlier, current.GeneratedAt.AddDate(-1, 0, 0), yearAgoTolerance); ok && !yearAgo.GeneratedAt.Equal(last.GeneratedAt) {
+		report.Comparisons = append(report.Comparisons, TrendComparison{Label: "Same period last year", Previous: yearAgo})
+	}
+
+	report.History = dailySnapshots(append(earlier, current))
+	return report
+}
+
+// compareWithHistory adds the trend report and its charts to an analysis
+func compareWithHistory(result *AnalysisResult, stored []TrendSnapshot, logger *Logger) {
+	result.Trends = BuildTrendReport(result, stored)
+	if result.Trends == nil {
+		logger.Info("No earlier analyses stored - trends will appear from the next run")
+		return
+	}
+
+	logger.Info("Compared with earlier analyses",
+		"runs", result.Trends.Runs,
+		"comparisons", len(result.Trends.Comparisons),
+	)
+
+	if len(result.Trends.History) < 2 {
+		return
+	}
+
+	chartGen := NewChartGenerator()
+	if usageChart, err := chartGen.GenerateTrendUsageChart(result.Trends.History); err == nil {
+		result.TrendUsageChart = usageChart
+	} else {
+		logger.Warn("Failed to generate trend usage chart", "error", err)
+	}
+	if costChart, err := chartGen.GenerateTrendCostChart(result.Trends.History); err == nil {
+		result.TrendCostChart = costChart
+	} else {
+		logger.Warn("Failed to generate trend cost chart", "error", err)
+	}
+}
+
+// nearestSnapshot returns the snapshot generated closest to target, if any is
+// within tolerance
+func nearestSnapshot(snapshots []TrendSnapshot, target time.Time, tolerance time.Duration) (TrendSnapshot, bool) {
+	var nearest TrendSnapshot
+	best := tolerance + 1
+	for _, snapshot := range snapshots {
+		distance := snapshot.GeneratedAt.Sub(target)
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance < best {
+			nearest, best = snapshot, distance
+		}
+	}
+	return nearest, best <= tolerance
+}
+
+// dailySnapshots keeps the last analysis of each UK day, so repeated runs
+// don't crowd the charts
+func dailySnapshots(snapshots []TrendSnapshot) []TrendSnapshot {
+	var daily []TrendSnapshot
+	for _, snapshot := range snapshots {
+		if n := len(daily); n > 0 && londonDate(daily[n-1].GeneratedAt) == londonDate(snapshot.GeneratedAt) {
+			daily[n-1] = snapshot
+			continue
+		}
+		daily = append(daily, snapshot)
+	}
+	return daily
+}
+
+// trackedMetrics returns the metrics with a value in any analysis of the
+// report, so electricity-only homes don't get a row of zero gas
+func trackedMetrics(report *TrendReport) []trendMetric {
+	var metrics []trendMetric
+	for _, metric := range trendMetrics {
+		tracked := metric.value(report.Current) != 0
+		for _, snapshot := range report.History {
+			tracked = tracked || metric.value(snapshot) != 0
+		}
+		for _, comparison := range report.Comparisons {
+			tracked = tracked || metric.value(comparison.Previous) != 0
+		}
+		if tracked {
+			metrics = append(metrics, metric)
+		}
+	}
+	return metrics
+}
+
+// formatTrendChange describes how a metric moved from previous to current
+func formatTrendChange(metric trendMetric, current, previous TrendSnapshot) string {
+	before := metric.value(previous)
+	difference := metric.value(current) - before
+
+	sign := "+"
+	if difference < 0 {
+		sign = "-"
+	}
+	change := sign + metric.format(math.Abs(difference))
+	if before != 0 {
+		change += fmt.Sprintf(" (%+.0f%%)", difference/math.Abs(before)*100)
+	}
+	return change
+}
+
+// comparisonHeading names a comparison and when the earlier analysis ran
+func comparisonHeading(comparison TrendComparison) string {
+	return fmt.Sprintf("vs %s (%s)", comparison.Label, comparison.Previous.GeneratedAt.In(london).Format("2 Jan 2006"))
+}
+
+// sparkline draws values as a row of block characters scaled between their
+// lowest and highest
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := values[0], values[0]
+	for _, value := range values {
+		low = math.Min(low, value)
+		high = math.Max(high, value)
+	}
+
+	var line strings.Builder
+	for _, value := range values {
+		level := len(sparkBlocks) / 2
+		if high > low {
+			level = int((value - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
+
+// recentSnapshots returns up to the last n snapshots
+func recentSnapshots(snapshots []TrendSnapshot, n int) []TrendSnapshot {
+	if len(snapshots) > n {
+		return snapshots[len(snapshots)-n:]
+	}
+	return snapshots
+}
+
+// metricValues extracts one metric from each snapshot
+func metricValues(metric trendMetric, snapshots []TrendSnapshot) []float64 {
+	values := make([]float64, len(snapshots))
+	for i, snapshot := range snapshots {
+		values[i] = metric.value(snapshot)
+	}
+	return values
+}
+
+// formatDailyKWh formats a daily energy figure
+func formatDailyKWh(value float64) string {
+	return fmt.Sprintf("%.1f kWh/day", value)
+}
+
+// formatDailyPounds formats a daily cost
+func formatDailyPounds(value float64) string {
+	return FormatCurrency(value) + "/day"
+}
+
+// formatMonthlyPounds formats a monthly payment
+func formatMonthlyPounds(value float64) string {
+	return FormatCurrency(value) + "/month"
+}
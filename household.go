@@ -0,0 +1,109 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// SelectAccounts narrows the configured accounts to the one with the given
+// account ID, or returns them all when no ID is given
+func SelectAccounts(accounts []*Config, accountID string) ([]*Config, error) {
+	if accountID == "" || len(accounts) <= 1 {
+		return accounts, nil
+	}
+
+	for _, account := range accounts {
+		if account.AccountID == accountID {
+			return []*Config{account}, nil
+		}
+	}
+
+	return nil, &ConfigError{
+		Field:   "accounts",
+		Message: fmt.Sprintf("account %s is not listed in the configuration", accountID),
+	}
+}
+
+// NewHouseholdResult totals the accounts that were analysed successfully.
+// Accounts that failed are kept so the report can say why.
+func NewHouseholdResult(accounts []HouseholdAccount) *HouseholdResult {
+	household := &HouseholdResult{
+		GeneratedAt: now(),
+		Accounts:    accounts,
+	}
+
+	for _, account := range accounts {
+		result := account.Result
+		if result == nil {
+			continue
+		}
+		household.AvgDailyCostTotal += result.AvgDailyCostTotal
+		household.ProjectedMonthlyCost += result.ProjectedMonthlyCost
+		household.CurrentBalance += result.CurrentBalance
+		household.CurrentDirectDebit += result.CurrentDirectDebit
+		household.RecommendedDirectDebit += result.RecommendedDirectDebit
+		household.AvgDailyElectricity += result.AvgDailyElectricity
+		household.AvgDailyExport += result.AvgDailyExport
+		household.AvgDailyGas += result.AvgDailyGas
+	}
+
+	return household
+}
+
+// Analysed returns the number of accounts with a result
+func (h *HouseholdResult) Analysed() int {
+	count := 0
+	for _, account := range h.Accounts {
+		if account.Result != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Share returns an account's percentage of the household's net daily cost
+func (h *HouseholdResult) Share(result *AnalysisResult) float64 {
+	if result == nil || h.AvgDailyCostTotal <= 0 {
+		return 0
+	}
+	return result.AvgDailyCostTotal / h.AvgDailyCostTotal * 100
+}
+
+// AccountReportPath derives the path of one account's own report from the
+// household report path, e.g. report.html becomes report-mum-dad.html for
+// "Mum & Dad"
+func AccountReportPath(outputPath string, account *Config) string {
+	ext := filepath.Ext(outputPath)
+	return strings.TrimSuffix(outputPath, ext) + "-" + account.ReportSlug() + ext
+}
+
+// slugify lowercases s and replaces runs of anything other than letters and
+// digits with a single hyphen
+func slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			hyphen = false
+		} else if b.Len() > 0 && !hyphen {
+			b.WriteRune('-')
+			hyphen = true
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
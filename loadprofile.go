@@ -0,0 +1,249 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// slotsPerDay is the number of half-hours in a day's load profile
+	slotsPerDay = 48
+
+	// DefaultLoadShiftPercent is the share of peak-band import assumed movable
+	DefaultLoadShiftPercent = 20.0
+
+	// minLoadShiftSaving is the smallest annual saving (pounds) worth suggesting
+	minLoadShiftSaving = 10.0
+)
+
+// bandOrder lists rate bands in the order reports show them
+var bandOrder = map[RateBand]int{
+	BandOffPeak:  0,
+	BandStandard: 1,
+	BandPeak:     2,
+}
+
+// BuildLoadProfile works out when electricity is imported: the average kWh in
+// each half-hour of the day on weekdays and at weekends, and how much import
+// and cost fell in each rate band. bandAt gives the band a slot was charged at.
+func BuildLoadProfile(consumptions []Consumption, bandAt func(time.Time) RateBand) *LoadProfile {
+	if len(consumptions) == 0 {
+		return nil
+	}
+
+	profile := &LoadProfile{
+		Weekday: make([]float64, slotsPerDay),
+		Weekend: make([]float64, slotsPerDay),
+	}
+
+	// Sum each slot of the day, counting days rather than readings so that
+	// readings pooled from several properties add up
+	weekdays := make(map[string]bool)
+	weekends := make(map[string]bool)
+	days := make(map[string]bool)
+	bands := make(map[RateBand]*BandUsage)
+	var totalKWh float64
+
+	for _, c := range consumptions {
+		local := c.StartAt.In(london)
+		slot := (local.Hour()*60 + local.Minute()) / 30
+		date := londonDate(c.StartAt)
+		days[date] = true
+
+		if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+			profile.Weekend[slot] += c.Value
+			weekends[date] = true
+		} else {
+			profile.Weekday[slot] += c.Value
+			weekdays[date] = true
+		}
+
+		band := bandAt(c.StartAt)
+		usage, ok := bands[band]
+		if !ok {
+			usage = &BandUsage{Band: band}
+			bands[band] = usage
+		}
+		usage.KWh += c.Value
+		usage.Cost += c.Cost / 100
+		totalKWh += c.Value
+	}
+
+	for slot := 0; slot < slotsPerDay; slot++ {
+		if len(weekdays) > 0 {
+			profile.Weekday[slot] /= float64(len(weekdays))
+		}
+		if len(weekends) > 0 {
+			profile.Weekend[slot] /= float64(len(weekends))
+		}
+	}
+	profile.Days = len(days)
+
+	for _, usage := range bands {
+		if totalKWh > 0 {
+			usage.SharePercent = usage.KWh / totalKWh * 100
+		}
+		if usage.KWh > 0 {
+			usage.AvgRate = usage.Cost * 100 / usage.KWh
+		}
+		profile.Bands = append(profile.Bands, *usage)
+	}
+	sort.Slice(profile.Bands, func(i, j int) bool {
+		return bandOrder[profile.Bands[i].Band] < bandOrder[profile.Bands[j].Band]
+	})
+
+	return profile
+}
+
+// EstimateLoadShift works out the annual saving if shiftPercent of the import
+// in the most expensive band moved to the cheapest, at the rates actually paid
+// in each band over the period
+func (p *LoadProfile) EstimateLoadShift(shiftPercent float64) {
+	p.ShiftPercent = shiftPercent
+	if len(p.Bands) < 2 || p.Days == 0 {
+		return
+	}
+
+	dearest, cheapest := p.Bands[0], p.Bands[0]
+	for _, usage := range p.Bands {
+		if usage.KWh == 0 {
+			continue
+		}
+		if dearest.KWh == 0 || usage.AvgRate > dearest.AvgRate {
+			dearest = usage
+		}
+		if cheapest.KWh == 0 || usage.AvgRate < cheapest.AvgRate {
+			cheapest = usage
+		}
+	}
+	if dearest.Band == cheapest.Band || dearest.AvgRate <= cheapest.AvgRate {
+		return
+	}
+
+	p.PeakBand = dearest.Band
+	p.CheapestBand = cheapest.Band
+
+	yearly := 365 / float64(p.Days)
+	p.ShiftKWh = dearest.KWh * shiftPercent / 100 * yearly
+	p.ShiftSaving = p.ShiftKWh * (dearest.AvgRate - cheapest.AvgRate) / 100
+}
+
+// Band returns the usage in a band, if any import fell in it
+func (p *LoadProfile) Band(band RateBand) (BandUsage, bool) {
+	for _, usage := range p.Bands {
+		if usage.Band == band {
+			return usage, true
+		}
+	}
+	return BandUsage{}, false
+}
+
+// analyzeLoadProfile builds the electricity load profile and estimates the
+// saving from moving peak use to the cheapest band
+func (a *Analyzer) analyzeLoadProfile(result *AnalysisResult, data *CollectedData) {
+	// Use the configured windows, or those of whichever tariff was active at the time
+	override, _ := a.config.PricingModelOverride()
+	bandAt := func(t time.Time) RateBand {
+		if override != nil {
+			return override.BandAt(t)
+		}
+		tariff := findActiveTariff(t, data.ElectricityAgreements)
+		if tariff == nil {
+			tariff = currentTariff(data.ElectricityAgreements)
+		}
+		return DetectPricingModel(tariff).BandAt(t)
+	}
+
+	profile := BuildLoadProfile(data.ElectricityConsumption, bandAt)
+	if profile == nil {
+		return
+	}
+
+	shiftPercent := a.config.LoadShiftPercent
+	if shiftPercent <= 0 {
+		shiftPercent = DefaultLoadShiftPercent
+	}
+	profile.EstimateLoadShift(shiftPercent)
+
+	result.LoadProfile = profile
+}
+
+// loadShiftInsight suggests moving use out of the most expensive band when
+// doing so would save a worthwhile amount
+func loadShiftInsight(result *AnalysisResult) (Insight, bool) {
+	profile := result.LoadProfile
+	if profile == nil || profile.ShiftSaving < minLoadShiftSaving {
+		return Insight{}, false
+	}
+
+	dearest, _ := profile.Band(profile.PeakBand)
+	cheapest, _ := profile.Band(profile.CheapestBand)
+
+	peak := strings.ToLower(bandLabel(profile.PeakBand))
+	cheap := strings.ToLower(bandLabel(profile.CheapestBand))
+
+	priority := "low"
+	if profile.ShiftSaving >= 50 {
+		priority = "medium"
+	}
+
+	return Insight{
+		Category: "load_shifting",
+		Priority: priority,
+		Title:    fmt.Sprintf("Move Use Out of the %s Band", bandLabel(profile.PeakBand)),
+		Description: fmt.Sprintf("%.0f%% of your import is in the %s band at %.1fp/kWh, while %s costs %.1fp/kWh. Moving %.0f%% of %s use (about %.0f kWh a year) would save around £%.0f a year.",
+			dearest.SharePercent,
+			peak,
+			dearest.AvgRate,
+			cheap,
+			cheapest.AvgRate,
+			profile.ShiftPercent,
+			peak,
+			profile.ShiftKWh,
+			profile.ShiftSaving,
+		),
+		Action: fmt.Sprintf("Put the dishwasher, washing machine, tumble dryer and any EV or battery charging on timers so they run in the %s band", cheap),
+	}, true
+}
+
+// bandLabel names a rate band for display
+func bandLabel(band RateBand) string {
+	switch band {
+	case BandOffPeak:
+		return "Off-peak"
+	case BandPeak:
+		return "Peak"
+	case BandStandard:
+		return "Standard"
+	}
+	return string(band)
+}
+
+// hourlyProfile sums a half-hourly profile into hours for compact tables
+func hourlyProfile(slots []float64) []float64 {
+	hours := make([]float64, len(slots)/2)
+	for i, value := range slots {
+		hours[i/2] += value
+	}
+	return hours
+}
+
+// slotLabel formats a half-hour of the day as HH:MM
+func slotLabel(slot int) string {
+	return fmt.Sprintf("%02d:%02d", slot/2, slot%2*30)
+}
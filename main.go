@@ -15,9 +15,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
@@ -27,6 +32,10 @@ func main() {
 	apiKey := flag.String("key", "", "Octopus Energy API Key (overrides config)")
 	outputPath := flag.String("output", "", "Output file for report (default: stdout)")
 	htmlOutput := flag.Bool("html", false, "Generate HTML report instead of Markdown")
+	compareTariffs := flag.Bool("compare-tariffs", false, "Reprice the analysis period under alternative tariffs instead of generating a report")
+	recordDir := flag.String("record", "", "Record all HTTP requests and responses to this fixture directory")
+	replayDir := flag.String("replay", "", "Replay HTTP responses from this fixture directory without network access")
+	timeout := flag.Duration("timeout", 0, "Abandon the run if data collection and analysis take longer than this (e.g. 2m; 0 disables)")
 	debug := flag.Bool("debug", false, "Enable debug logging")
 	showVersion := flag.Bool("version", false, "Show version and exit")
 
@@ -42,9 +51,6 @@ func main() {
 	logger := NewLogger(*debug)
 	logger.Info("Starting octobudget", "version", GetVersion())
 
-	// Check for updates (non-blocking)
-	go CheckForUpdates(logger)
-
 	// Load configuration
 	logger.Info("Loading configuration", "config_file", *configPath)
 	config, err := LoadConfig(*configPath)
@@ -60,6 +66,14 @@ func main() {
 	if *apiKey != "" {
 		config.APIKey = *apiKey
 	}
+	if *recordDir != "" {
+		config.FixtureMode = FixtureModeRecord
+		config.FixturePath = *recordDir
+	}
+	if *replayDir != "" {
+		config.FixtureMode = FixtureModeReplay
+		config.FixturePath = *replayDir
+	}
 	if *debug {
 		config.Debug = true
 		// Recreate logger with debug enabled
@@ -81,41 +95,180 @@ func main() {
 
 	logger.Info("Configuration loaded successfully")
 
-	// Initialize storage
-	logger.Info("Initializing storage", "path", config.StoragePath)
-	storage, err := NewStorage(config.StoragePath, config.AccountID, logger)
+	// Check for updates (non-blocking, skipped for fixture runs to keep them reproducible)
+	if config.FixtureMode == "" {
+		go CheckForUpdates(config.ReleasesURL, logger)
+	}
+
+	// Set up HTTP record/replay if requested
+	transport, err := setupFixtures(config, logger)
 	if err != nil {
-		logger.Error("Failed to initialize storage", "error", err)
+		logger.Error("Failed to set up HTTP fixtures", "error", err)
 		os.Exit(1)
 	}
-	defer storage.Close()
 
-	// Create GraphQL client
-	logger.Info("Creating API client")
-	client := NewOctopusClient(config.AccountID, config.APIKey, logger)
+	// Stop cleanly on Ctrl-C, and enforce the overall deadline if one was given
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
 
-	// Create data collector
-	logger.Info("Initializing data collector")
-	collector := NewCollector(client, config, storage, logger)
+	// With several accounts configured, -account picks one of them
+	accounts, err := SelectAccounts(config.AccountConfigs(), *accountID)
+	if err != nil {
+		logger.Error("Failed to select account", "error", err)
+		os.Exit(1)
+	}
 
-	// Fetch all data from API
-	logger.Info("Collecting data from Octopus Energy API")
-	data, err := collector.CollectAll()
+	// Tariff comparison replaces the normal analysis and report
+	if *compareTariffs {
+		account := accounts[0]
+		if len(accounts) > 1 {
+			logger.Warn("Comparing tariffs for the first account only - use -account to choose another",
+				"account", account.AccountLabel(),
+			)
+		}
+		if *htmlOutput {
+			logger.Warn("HTML output is not supported for tariff comparisons, writing Markdown")
+		}
+
+		if err := runTariffComparison(ctx, account, transport, *outputPath, logger); err != nil {
+			logger.Error("Failed to compare tariffs", "error", err)
+			os.Exit(1)
+		}
+
+		logger.Info("Tariff comparison completed successfully")
+		return
+	}
+
+	if len(accounts) == 1 {
+		result, err := runAccount(ctx, accounts[0], transport, logger)
+		if err != nil {
+			logger.Error("Failed to analyse account", "error", err)
+			os.Exit(1)
+		}
+
+		if err := writeReport(result, *outputPath, *htmlOutput, logger); err != nil {
+			logger.Error("Failed to generate report", "error", err)
+			os.Exit(1)
+		}
+
+		logger.Info("Analysis completed successfully")
+		return
+	}
+
+	// Each account gets its own run, stored results and (when writing to a file)
+	// its own report, followed by a combined household report
+	logger.Info("Analysing household", "accounts", len(accounts))
+	household := make([]HouseholdAccount, 0, len(accounts))
+	for _, account := range accounts {
+		entry := HouseholdAccount{Name: account.AccountLabel(), AccountID: account.AccountID}
+
+		result, err := runAccount(ctx, account, transport, logger)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.Error("Household analysis interrupted", "account", entry.Name, "error", err)
+				os.Exit(1)
+			}
+			logger.Error("Failed to analyse account", "account", entry.Name, "error", err)
+			entry.Error = err.Error()
+			household = append(household, entry)
+			continue
+		}
+		entry.Result = result
+
+		if *outputPath != "" {
+			entry.ReportPath = AccountReportPath(*outputPath, account)
+			if err := writeReport(result, entry.ReportPath, *htmlOutput, logger); err != nil {
+				logger.Warn("Failed to generate account report", "account", entry.Name, "error", err)
+				entry.ReportPath = ""
+			}
+		}
+
+		household = append(household, entry)
+	}
+
+	householdResult := NewHouseholdResult(household)
+	if householdResult.Analysed() == 0 {
+		logger.Error("No account in the household could be analysed")
+		os.Exit(1)
+	}
+
+	if *htmlOutput {
+		logger.Info("Generating HTML household report")
+		err = NewHTMLReporter(logger).GenerateHTMLHouseholdReport(householdResult, *outputPath)
+	} else {
+		logger.Info("Generating Markdown household report")
+		err = NewReporter(logger).GenerateHouseholdReport(householdResult, *outputPath)
+	}
 	if err != nil {
-		logger.Error("Failed to collect data", "error", err)
+		logger.Error("Failed to generate household report", "error", err)
 		os.Exit(1)
 	}
 
-	// Create analyzer
-	logger.Info("Initializing analyzer")
-	analyzer := NewAnalyzer(config, logger)
+	logger.Info("Household analysis completed successfully",
+		"accounts", len(household),
+		"analysed", householdResult.Analysed(),
+	)
+}
+
+// newAccountCollector opens an account's storage and creates its API client and collector
+func newAccountCollector(config *Config, transport http.RoundTripper, logger *Logger) (*Collector, *Storage, error) {
+	logger.Info("Initializing storage", "path", config.StoragePath, "account", config.AccountLabel())
+	storage, err := NewStorage(config.StoragePath, config.AccountID, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	// Fixture runs must reach the HTTP layer for every request
+	if config.FixtureMode != "" {
+		storage.DisableCache()
+	}
+
+	logger.Info("Creating API client")
+	client := NewOctopusClient(config, transport, logger)
+
+	return NewCollector(client, config, storage, logger), storage, nil
+}
+
+// runAccount collects, analyses and stores one account's data
+func runAccount(ctx context.Context, config *Config, transport http.RoundTripper, logger *Logger) (*AnalysisResult, error) {
+	collector, storage, err := newAccountCollector(config, transport, logger)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.Close()
+
+	// Fetch all data from API
+	logger.Info("Collecting data from Octopus Energy API", "account", config.AccountLabel())
+	data, err := collector.CollectAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect data: %w", err)
+	}
 
 	// Perform analysis
-	logger.Info("Performing analysis")
-	result, err := analyzer.Analyze(data)
+	logger.Info("Performing analysis", "account", config.AccountLabel())
+	analyzer := NewAnalyzer(config, NewWeatherClient(config, transport, logger), logger)
+	result, err := analyzer.Analyze(ctx, data)
 	if err != nil {
-		logger.Error("Failed to perform analysis", "error", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to perform analysis: %w", err)
+	}
+
+	// Fixture runs must reproduce the recorded report exactly, so they neither
+	// read the local history nor add to it
+	if config.FixtureMode != "" {
+		logger.Info("Skipping analysis history in fixture mode", "mode", config.FixtureMode)
+		return result, nil
+	}
+
+	// Compare with earlier runs before this one joins them
+	if history, err := storage.LoadAnalysisHistory(config.AccountID); err == nil {
+		compareWithHistory(result, history, logger)
+	} else {
+		logger.Warn("Failed to load analysis history", "error", err)
 	}
 
 	// Save analysis results
@@ -124,22 +277,90 @@ func main() {
 		logger.Warn("Failed to save analysis results", "error", err)
 	}
 
-	// Generate report (HTML or Markdown)
-	if *htmlOutput {
+	return result, nil
+}
+
+// runTariffComparison reprices one account's usage under alternative tariffs
+// and writes the comparison
+func runTariffComparison(ctx context.Context, config *Config, transport http.RoundTripper, outputPath string, logger *Logger) error {
+	collector, storage, err := newAccountCollector(config, transport, logger)
+	if err != nil {
+		return err
+	}
+	defer storage.Close()
+
+	logger.Info("Collecting data from Octopus Energy API")
+	data, err := collector.CollectAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to collect data: %w", err)
+	}
+
+	// Usage from different properties can't be repriced as one household
+	target := data
+	if len(data.Properties) > 1 {
+		target = data.Properties[0]
+		logger.Warn("Comparing tariffs for the first property only - set properties in config.yaml to choose another",
+			"property", Property{ID: target.PropertyID, Address: target.PropertyAddress}.Label(),
+		)
+	}
+
+	logger.Info("Comparing alternative tariffs")
+	comparison, err := collector.CompareTariffs(ctx, target, config.TariffCandidates())
+	if err != nil {
+		return err
+	}
+
+	return NewReporter(logger).GenerateTariffComparison(comparison, outputPath)
+}
+
+// writeReport writes one account's report as HTML or Markdown
+func writeReport(result *AnalysisResult, outputPath string, htmlOutput bool, logger *Logger) error {
+	if htmlOutput {
 		logger.Info("Generating HTML report")
-		htmlReporter := NewHTMLReporter(logger)
-		if err := htmlReporter.GenerateHTMLReport(result, *outputPath); err != nil {
-			logger.Error("Failed to generate HTML report", "error", err)
-			os.Exit(1)
+		return NewHTMLReporter(logger).GenerateHTMLReport(result, outputPath)
+	}
+
+	logger.Info("Generating Markdown report")
+	return NewReporter(logger).GenerateReport(result, outputPath)
+}
+
+// setupFixtures creates the record/replay transport and pins the clock to the
+// session time so that recorded and replayed runs produce identical requests
+func setupFixtures(config *Config, logger *Logger) (http.RoundTripper, error) {
+	if config.FixtureMode == "" {
+		return nil, nil
+	}
+
+	// Every account's API key is redacted from recordings
+	var secrets []string
+	for _, account := range config.AccountConfigs() {
+		secrets = append(secrets, account.APIKey)
+	}
+
+	fixtures, err := NewFixtureTransport(config.FixtureMode, config.FixturePath, secrets, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessionTime time.Time
+	if config.FixtureMode == FixtureModeReplay {
+		session, err := LoadFixtureSession(config.FixturePath)
+		if err != nil {
+			return nil, err
 		}
+		sessionTime = session.RecordedAt
+		logger.Info("Replaying recorded session",
+			"recorded_at", sessionTime.Format(time.RFC3339),
+			"recorded_version", session.Version,
+		)
 	} else {
-		logger.Info("Generating Markdown report")
-		reporter := NewReporter(logger)
-		if err := reporter.GenerateReport(result, *outputPath); err != nil {
-			logger.Error("Failed to generate report", "error", err)
-			os.Exit(1)
+		sessionTime = time.Now()
+		if err := SaveFixtureSession(config.FixturePath, sessionTime); err != nil {
+			return nil, err
 		}
 	}
 
-	logger.Info("Analysis completed successfully")
+	now = func() time.Time { return sessionTime }
+
+	return fixtures, nil
 }
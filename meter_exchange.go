@@ -0,0 +1,198 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// serialReadings is the consumption reported under one meter serial
+type serialReadings struct {
+	Serial       string
+	Unit         string // Unit the serial reports in, set for gas
+	Consumptions []Consumption
+}
+
+// StitchedReadings is a meter point's consumption merged across every serial
+// that has reported on it
+type StitchedReadings struct {
+	Consumptions []Consumption
+	Segments     []MeterSegment  // The range each serial supplied, in the order they took over
+	Exchanges    []MeterExchange // Where each new serial took over
+	Overlaps     int             // Slots reported by more than one serial
+}
+
+// StitchReadings merges readings from several serials on one meter point by
+// interval. When serials overlap, a non-zero reading beats a zero one (a
+// removed meter often keeps reporting zeros), and otherwise the serial that
+// started reporting later wins, since the newer meter has taken over.
+func StitchReadings(fuelType string, series []serialReadings) StitchedReadings {
+	var stitched StitchedReadings
+
+	// Order serials by when they started reporting
+	var active []serialReadings
+	firstReading := make(map[string]time.Time)
+	for _, s := range series {
+		if len(s.Consumptions) == 0 {
+			continue
+		}
+		first := s.Consumptions[0].StartAt
+		for _, c := range s.Consumptions {
+			if c.StartAt.Before(first) {
+				first = c.StartAt
+			}
+		}
+		firstReading[s.Serial] = first
+		active = append(active, s)
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return firstReading[active[i].Serial].Before(firstReading[active[j].Serial])
+	})
+
+	units := make(map[string]string, len(active))
+	for _, s := range active {
+		units[s.Serial] = s.Unit
+	}
+
+	// A single serial needs no merging, but still records where it came from
+	if len(active) == 1 {
+		stitched.Consumptions = active[0].Consumptions
+		stitched.Segments = buildSegments(fuelType, stitched.Consumptions, func(int) string { return active[0].Serial }, units)
+		return stitched
+	}
+
+	type slot struct {
+		reading Consumption
+		serial  string
+	}
+	slots := make(map[int64]*slot)
+	for _, s := range active {
+		for _, c := range s.Consumptions {
+			key := c.StartAt.Unix()
+			existing, ok := slots[key]
+			if !ok {
+				slots[key] = &slot{reading: c, serial: s.Serial}
+				continue
+			}
+
+			stitched.Overlaps++
+			// Later serials win unless they'd replace a real reading with zero
+			if c.Value == 0 && existing.reading.Value != 0 {
+				continue
+			}
+			existing.reading, existing.serial = c, s.Serial
+		}
+	}
+
+	keys := make([]int64, 0, len(slots))
+	for key := range slots {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	serials := make([]string, len(keys))
+	stitched.Consumptions = make([]Consumption, len(keys))
+	for i, key := range keys {
+		stitched.Consumptions[i] = slots[key].reading
+		serials[i] = slots[key].serial
+	}
+
+	stitched.Segments = buildSegments(fuelType, stitched.Consumptions, func(i int) string { return serials[i] }, units)
+	for i := 1; i < len(stitched.Segments); i++ {
+		previous, current := stitched.Segments[i-1], stitched.Segments[i]
+		stitched.Exchanges = append(stitched.Exchanges, MeterExchange{
+			FuelType:  fuelType,
+			Date:      current.Start,
+			OldSerial: previous.Serial,
+			NewSerial: current.Serial,
+		})
+	}
+
+	return stitched
+}
+
+// buildSegments works out the range of readings each serial supplied, ordered
+// by when each took over. Overlap resolution can switch back and forth around
+// a swap, so a serial's range runs from its first reading to its last.
+func buildSegments(fuelType string, consumptions []Consumption, serialAt func(int) string, units map[string]string) []MeterSegment {
+	var segments []MeterSegment
+	index := make(map[string]int)
+	for i, c := range consumptions {
+		serial := serialAt(i)
+		if n, ok := index[serial]; ok {
+			segments[n].End = c.EndAt
+			segments[n].Readings++
+			continue
+		}
+		index[serial] = len(segments)
+		segments = append(segments, MeterSegment{
+			FuelType: fuelType,
+			Serial:   serial,
+			Start:    c.StartAt,
+			End:      c.EndAt,
+			Readings: 1,
+			Unit:     units[serial],
+		})
+	}
+	return segments
+}
+
+// meterExchangeSegments returns the ranges supplied by serials on meter points
+// that had an exchange, leaving out meters that were never swapped
+func meterExchangeSegments(result *AnalysisResult) []MeterSegment {
+	exchanged := make(map[string]bool)
+	for _, exchange := range result.MeterExchanges {
+		exchanged[exchange.FuelType+"/"+exchange.OldSerial] = true
+		exchanged[exchange.FuelType+"/"+exchange.NewSerial] = true
+	}
+
+	var segments []MeterSegment
+	for _, segment := range result.MeterSegments {
+		if exchanged[segment.FuelType+"/"+segment.Serial] {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// meterSerials lists the serials to fetch for a meter point: the chosen serial
+// first, followed by any others installed on it over time
+func meterSerials(primary string, meters []Meter) []string {
+	var serials []string
+	if primary != "" {
+		serials = append(serials, primary)
+	}
+	for _, meter := range meters {
+		if meter.SerialNumber != "" && meter.SerialNumber != primary {
+			serials = append(serials, meter.SerialNumber)
+		}
+	}
+	return serials
+}
+
+// firstFetchError returns the first error from a set of per-serial fetches,
+// or nil when any serial returned readings
+func firstFetchError(series []serialReadings, errs []error) error {
+	var first error
+	for i, err := range errs {
+		if err == nil && len(series[i].Consumptions) > 0 {
+			return nil
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
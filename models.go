@@ -49,8 +49,9 @@ type GasMeterPoint struct {
 
 // Meter represents a physical meter
 type Meter struct {
-	SerialNumber string        `json:"serialNumber"`
-	SmartDevices []SmartDevice `json:"smartDevices"`
+	SerialNumber     string        `json:"serialNumber"`
+	ConsumptionUnits string        `json:"consumptionUnits,omitempty"` // Units the meter reports in (gas: m³ or kWh)
+	SmartDevices     []SmartDevice `json:"smartDevices"`
 }
 
 // SmartDevice represents a smart meter device
@@ -69,7 +70,8 @@ type Agreement struct {
 type Tariff struct {
 	DisplayName    string  `json:"displayName"`
 	FullName       string  `json:"fullName"`
-	StandingCharge float64 `json:"standingCharge"` // Pence per day
+	TariffCode     string  `json:"tariffCode,omitempty"` // e.g. E-1R-VAR-22-11-01-C (last letter is the region)
+	StandingCharge float64 `json:"standingCharge"`       // Pence per day
 	UnitRate       float64 `json:"unitRate"`       // Pence per kWh (for simple tariffs)
 	DayRate        float64 `json:"dayRate"`        // Pence per kWh (for day/night tariffs)
 	NightRate      float64 `json:"nightRate"`      // Pence per kWh (for day/night tariffs)
@@ -78,10 +80,17 @@ type Tariff struct {
 
 // Consumption represents energy consumption data
 type Consumption struct {
-	StartAt time.Time `json:"startAt"`
-	EndAt   time.Time `json:"endAt"`
-	Value   float64   `json:"value"` // kWh
-	Cost    float64   `json:"cost"`  // Pence
+	StartAt  time.Time `json:"startAt"`
+	EndAt    time.Time `json:"endAt"`
+	Value    float64   `json:"value"`              // kWh
+	Cost     float64   `json:"cost"`               // Pence
+	RawValue float64   `json:"rawValue,omitempty"` // Meter reading before conversion to kWh (gas: m³)
+}
+
+// DailyStandingCharge represents the standing charge applied for a single day
+type DailyStandingCharge struct {
+	Date   time.Time `json:"date"`
+	Charge float64   `json:"charge"` // Pence
 }
 
 // Statement represents a billing statement
@@ -104,17 +113,54 @@ type Payment struct {
 	Method      string    `json:"method"`
 }
 
+// PaymentSchedule represents the account's active Direct Debit schedule
+type PaymentSchedule struct {
+	ID                  string     `json:"id"`
+	Amount              float64    `json:"amount"`              // Pounds per collection
+	Frequency           string     `json:"frequency"`           // Weekly, Monthly, Quarterly or Yearly
+	FrequencyMultiplier int        `json:"frequencyMultiplier"` // Collections every N periods
+	PaymentDay          int        `json:"paymentDay"`          // Day of the month collections are taken
+	IsVariable          bool       `json:"isVariable"`          // Amount varies with each bill
+	ValidFrom           time.Time  `json:"validFrom"`
+	ValidTo             *time.Time `json:"validTo,omitempty"`
+	NextPaymentDate     time.Time  `json:"nextPaymentDate,omitempty"`
+}
+
+// Dispatch represents a completed Intelligent Octopus smart-charge dispatch
+type Dispatch struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Delta  float64   `json:"delta"`  // kWh the device was scheduled to charge
+	Source string    `json:"source"` // smart-charge, bump-charge, etc.
+}
+
 // CollectedData holds all data fetched from the API
 type CollectedData struct {
 	Account                *Account      `json:"account"`
 	ElectricityConsumption []Consumption `json:"electricityConsumption"` // Import/consumption
 	ElectricityExport      []Consumption `json:"electricityExport"`      // Solar/battery export
-	GasConsumption         []Consumption `json:"gasConsumption"`
+	GasConsumption         []Consumption `json:"gasConsumption"`         // kWh, with the meter reading in RawValue when converted
+	GasUnit                string        `json:"gasUnit,omitempty"`       // Unit the gas meter reports in (m³ or kWh)
+	GasUnitSource          string        `json:"gasUnitSource,omitempty"` // config, meter details or default
 	ElectricityAgreements  []Agreement   `json:"electricityAgreements"`
 	ElectricityExportAgreements []Agreement `json:"electricityExportAgreements"`
 	GasAgreements          []Agreement   `json:"gasAgreements"`
+	ElectricityStandingCharges []DailyStandingCharge `json:"electricityStandingCharges"`
+	GasStandingCharges         []DailyStandingCharge `json:"gasStandingCharges"`
+	Region                 string        `json:"region"`       // GSP region letter used for Products API rates
+	RegionSource           string        `json:"regionSource"` // How the region was determined
+	PricingModel           string        `json:"pricingModel"` // Time-of-use windows used for agreement-rate pricing
 	Statements             []Statement   `json:"statements"`
 	Payments               []Payment     `json:"payments"`
+	PaymentSchedule        *PaymentSchedule `json:"paymentSchedule"`
+	Dispatches             []Dispatch    `json:"dispatches"`      // Completed smart-charge dispatches in the period
+	DispatchKWh            float64       `json:"dispatchKWh"`     // Import repriced at off-peak because of dispatches
+	DispatchSavings        float64       `json:"dispatchSavings"` // Pence saved by dispatch repricing
+	MeterSegments          []MeterSegment  `json:"meterSegments,omitempty"`  // Which serial supplied each range of readings
+	MeterExchanges         []MeterExchange `json:"meterExchanges,omitempty"` // Meter swaps found while merging serials
+	PropertyID             string        `json:"propertyId,omitempty"`      // Property the meters belong to
+	PropertyAddress        string        `json:"propertyAddress,omitempty"`
+	Properties             []*CollectedData `json:"properties,omitempty"`   // Per-property data when several are collected; this is then the rollup
 	FetchedAt              time.Time     `json:"fetchedAt"`
 }
 
@@ -128,10 +174,16 @@ type AnalysisResult struct {
 	AvgDailyElectricity         float64        `json:"avgDailyElectricity"`     // kWh (import)
 	AvgDailyExport              float64        `json:"avgDailyExport"`          // kWh (solar/battery export)
 	AvgDailyGas                 float64        `json:"avgDailyGas"`             // kWh
+	AvgDailyGasVolume           float64        `json:"avgDailyGasVolume,omitempty"` // m³, when the gas meter reports volume
+	GasUnit                     string         `json:"gasUnit,omitempty"`           // Unit the gas meter reports in (m³ or kWh)
+	GasUnitSource               string         `json:"gasUnitSource,omitempty"`     // config, meter details or default
+	GasCalorificValue           float64        `json:"gasCalorificValue,omitempty"` // MJ/m³, average used for conversion
 	AvgDailyCostElectricity     float64        `json:"avgDailyCostElectricity"` // Pounds (import cost)
 	AvgDailyEarningsExport      float64        `json:"avgDailyEarningsExport"`  // Pounds (export earnings)
 	AvgDailyCostGas             float64        `json:"avgDailyCostGas"`         // Pounds
-	AvgDailyCostTotal           float64        `json:"avgDailyCostTotal"`       // Pounds (net: import - export + gas)
+	AvgDailyStandingElectricity float64        `json:"avgDailyStandingElectricity"` // Pounds (electricity standing charge)
+	AvgDailyStandingGas         float64        `json:"avgDailyStandingGas"`         // Pounds (gas standing charge)
+	AvgDailyCostTotal           float64        `json:"avgDailyCostTotal"`       // Pounds (net: import - export + gas + standing charges)
 	ProjectedMonthlyCost        float64        `json:"projectedMonthlyCost"`    // Pounds
 	RecommendedDirectDebit      float64        `json:"recommendedDirectDebit"`  // Pounds
 	CurrentDirectDebit          float64        `json:"currentDirectDebit"`      // Pounds
@@ -139,23 +191,217 @@ type AnalysisResult struct {
 	ElectricityAgreements       []Agreement    `json:"electricityAgreements"`
 	ElectricityExportAgreements []Agreement    `json:"electricityExportAgreements"`
 	GasAgreements               []Agreement    `json:"gasAgreements"`
+	Region                      string         `json:"region,omitempty"`       // GSP region letter used for pricing
+	RegionSource                string         `json:"regionSource,omitempty"` // config, tariff code, MPAN distributor ID or default
+	PricingModel                string         `json:"pricingModel,omitempty"` // Time-of-use windows used for agreement-rate pricing
+	Statements                  []Statement    `json:"statements,omitempty"`   // Recent statements, newest first
+	Payments                    []Payment      `json:"payments,omitempty"`     // Recent payments, newest first
+	PaymentSchedule             *PaymentSchedule `json:"paymentSchedule,omitempty"`     // Active Direct Debit schedule from the account
+	DirectDebitSource           string           `json:"directDebitSource,omitempty"`   // account or config
+	ScheduledDirectDebit        float64          `json:"scheduledDirectDebit,omitempty"`  // Pounds the account collects, set when it disagrees with the config
+	DispatchCount               int              `json:"dispatchCount,omitempty"`         // Completed smart-charge dispatches in the period
+	DispatchKWh                 float64          `json:"dispatchKWh,omitempty"`           // Import repriced at off-peak because of dispatches
+	DispatchSavings             float64          `json:"dispatchSavings,omitempty"`       // Pounds saved by dispatch repricing
+	PropertyID                  string            `json:"propertyId,omitempty"`
+	PropertyAddress             string            `json:"propertyAddress,omitempty"`
+	Properties                  []*AnalysisResult `json:"properties,omitempty"` // Per-property results when several were analysed; this is then the rollup
+	MeterSegments               []MeterSegment  `json:"meterSegments,omitempty"`  // Which serial supplied each range of readings
+	MeterExchanges              []MeterExchange `json:"meterExchanges,omitempty"` // Meter swaps in the period
+	BaseloadWatts               float64        `json:"baseloadWatts,omitempty"`       // Typical always-on load over the period
+	BaseloadStartWatts          float64        `json:"baseloadStartWatts,omitempty"`  // Baseload over the first two weeks of the period
+	BaseloadRecentWatts         float64        `json:"baseloadRecentWatts,omitempty"` // Baseload over the last two weeks of the period
+	BaseloadAnnualKWh           float64        `json:"baseloadAnnualKWh,omitempty"`   // kWh a year at the recent baseload
+	BaseloadAnnualCost          float64        `json:"baseloadAnnualCost,omitempty"`  // Pounds a year at current rates
+	BaseloadDaily               []BaseloadDay  `json:"baseloadDaily,omitempty"`       // Each night's baseload estimate
+	HeatingModels               []HeatingModel `json:"heatingModels,omitempty"`       // Usage fitted against heating degree-days
+	LoadProfile                 *LoadProfile   `json:"loadProfile,omitempty"`         // When electricity is imported and what each rate band costs
+	Forecast                    *BalanceForecast `json:"forecast,omitempty"`          // Month-by-month cost and balance to the account anniversary
+	Trends                      *TrendReport     `json:"trends,omitempty"`            // Changes since earlier stored analyses
+	Budget                      *BudgetTracking  `json:"budget,omitempty"`            // Spend against target_daily_spend or monthly_budget
+	DataCoverage                []DataCoverage `json:"dataCoverage,omitempty"`   // How completely each meter's readings cover the period
+	DataConfidence              string         `json:"dataConfidence,omitempty"` // high, medium or low, from the weakest coverage
 	Anomalies                   []Anomaly      `json:"anomalies"`
 	TariffChanges               []TariffChange `json:"tariffChanges"`
 	Insights                    []Insight      `json:"insights"`
 	// Charts (base64 encoded PNG images)
 	DailyUsageChart string `json:"dailyUsageChart,omitempty"`
 	DailyCostChart  string `json:"dailyCostChart,omitempty"`
+	LoadProfileChart string `json:"loadProfileChart,omitempty"`
+	BalanceForecastChart string `json:"balanceForecastChart,omitempty"`
+	TrendUsageChart      string `json:"trendUsageChart,omitempty"`
+	TrendCostChart       string `json:"trendCostChart,omitempty"`
+}
+
+// HouseholdResult compares several accounts analysed in one run
+type HouseholdResult struct {
+	GeneratedAt            time.Time          `json:"generatedAt"`
+	Accounts               []HouseholdAccount `json:"accounts"`
+	AvgDailyCostTotal      float64            `json:"avgDailyCostTotal"`      // Pounds, across analysed accounts
+	ProjectedMonthlyCost   float64            `json:"projectedMonthlyCost"`   // Pounds
+	CurrentBalance         float64            `json:"currentBalance"`         // Pounds
+	CurrentDirectDebit     float64            `json:"currentDirectDebit"`     // Pounds per month
+	RecommendedDirectDebit float64            `json:"recommendedDirectDebit"` // Pounds per month
+	AvgDailyElectricity    float64            `json:"avgDailyElectricity"`    // kWh (import)
+	AvgDailyExport         float64            `json:"avgDailyExport"`         // kWh
+	AvgDailyGas            float64            `json:"avgDailyGas"`            // kWh
+}
+
+// HouseholdAccount is one account's part of a household run
+type HouseholdAccount struct {
+	Name       string          `json:"name"`
+	AccountID  string          `json:"accountId"`
+	Result     *AnalysisResult `json:"result,omitempty"`
+	ReportPath string          `json:"reportPath,omitempty"` // Where the account's own report was written
+	Error      string          `json:"error,omitempty"`      // Why the account could not be analysed
+}
+
+// MeterSegment is a range of readings supplied by one meter serial
+type MeterSegment struct {
+	FuelType string    `json:"fuelType"` // electricity, export or gas
+	Serial   string    `json:"serial"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Readings int       `json:"readings"`
+	Unit     string    `json:"unit,omitempty"` // Unit the serial reports in (gas only)
+}
+
+// HeatingModel is a fuel's daily usage fitted against heating degree-days
+type HeatingModel struct {
+	FuelType            string             `json:"fuelType"`
+	Days                int                `json:"days"`                // Days with readings and weather
+	BaseTemp            float64            `json:"baseTemp"`            // °C below which degree-days accrue
+	BaseloadKWh         float64            `json:"baseloadKWh"`         // kWh a day used whatever the weather
+	KWhPerDegreeDay     float64            `json:"kwhPerDegreeDay"`     // Extra kWh for each degree-day
+	RSquared            float64            `json:"rSquared"`            // How well the weather explains daily usage
+	DegreeDays          float64            `json:"degreeDays"`          // Degree-days over the fitted days
+	HeatingSharePercent float64            `json:"heatingSharePercent"` // Share of usage driven by the weather
+	NormalisedAnnualKWh float64            `json:"normalisedAnnualKWh"` // kWh in a typical year's weather
+	Comparison          *WeatherComparison `json:"comparison,omitempty"`
+}
+
+// WeatherComparison compares the recent half of the period with what the
+// earlier half's usage predicts for the recent weather
+type WeatherComparison struct {
+	EarlierStart           time.Time `json:"earlierStart"`
+	EarlierEnd             time.Time `json:"earlierEnd"`
+	RecentStart            time.Time `json:"recentStart"`
+	RecentEnd              time.Time `json:"recentEnd"`
+	EarlierKWhPerDegreeDay float64   `json:"earlierKWhPerDegreeDay"`
+	RecentKWhPerDegreeDay  float64   `json:"recentKWhPerDegreeDay,omitempty"` // Unset when the recent weather was too mild to fit
+	RecentActualKWh        float64   `json:"recentActualKWh"`
+	RecentExpectedKWh      float64   `json:"recentExpectedKWh"` // From the earlier fit and the recent weather
+	ChangePercent          float64   `json:"changePercent"`
+}
+
+// BalanceForecast projects cost and account balance month by month, and the
+// Direct Debit that reaches the target balance at the account anniversary
+type BalanceForecast struct {
+	StartBalance           float64         `json:"startBalance"`           // Pounds, today
+	TargetBalance          float64         `json:"targetBalance"`          // Pounds, at the anniversary
+	TargetDate             time.Time       `json:"targetDate"`             // Account anniversary the recommendation aims at
+	AnniversarySource      string          `json:"anniversarySource,omitempty"` // config or agreements (empty when a year ahead is assumed)
+	PaymentDay             int             `json:"paymentDay"`             // Day of the month collections are taken
+	Payments               int             `json:"payments"`               // Collections before the anniversary
+	CostToTarget           float64         `json:"costToTarget"`           // Pounds, forecast cost up to the anniversary
+	TargetBalanceCurrent   float64         `json:"targetBalanceCurrent"`   // Pounds at the anniversary on the current Direct Debit
+	RecommendedDirectDebit float64         `json:"recommendedDirectDebit"` // Pounds per month
+	Months                 []ForecastMonth `json:"months"`
+}
+
+// ForecastMonth is one calendar month of a balance forecast
+type ForecastMonth struct {
+	Month              time.Time `json:"month"`              // First of the month, UK time
+	Days               int       `json:"days"`               // Days forecast (partial at either end)
+	DegreeDays         float64   `json:"degreeDays"`         // Typical heating degree-days
+	ElectricityKWh     float64   `json:"electricityKWh"`
+	GasKWh             float64   `json:"gasKWh"`
+	Cost               float64   `json:"cost"`               // Pounds, net of export
+	BalanceCurrent     float64   `json:"balanceCurrent"`     // Pounds at month end on the current Direct Debit
+	BalanceRecommended float64   `json:"balanceRecommended"` // Pounds at month end on the recommended Direct Debit
+}
+
+// LoadProfile describes when electricity is imported over the period
+type LoadProfile struct {
+	Weekday      []float64   `json:"weekday"` // Average kWh in each half-hour of the day (UK time), Monday to Friday
+	Weekend      []float64   `json:"weekend"` // Average kWh in each half-hour of the day (UK time), Saturday and Sunday
+	Days         int         `json:"days"`    // Days with readings
+	Bands        []BandUsage `json:"bands"`   // Import in each rate band
+	ShiftPercent float64     `json:"shiftPercent"`           // Share of the dearest band's import assumed movable
+	PeakBand     RateBand    `json:"peakBand,omitempty"`     // Most expensive band actually used
+	CheapestBand RateBand    `json:"cheapestBand,omitempty"` // Cheapest band actually used
+	ShiftKWh     float64     `json:"shiftKWh,omitempty"`     // kWh a year moved
+	ShiftSaving  float64     `json:"shiftSaving,omitempty"`  // Pounds a year saved by moving it
+}
+
+// BandUsage is the import charged in one rate band
+type BandUsage struct {
+	Band         RateBand `json:"band"`
+	KWh          float64  `json:"kwh"`
+	Cost         float64  `json:"cost"`         // Pounds
+	SharePercent float64  `json:"sharePercent"` // Of all import
+	AvgRate      float64  `json:"avgRate"`      // Pence per kWh actually paid
+}
+
+// BaseloadDay is one night's always-on load estimate
+type BaseloadDay struct {
+	Date  time.Time `json:"date"`
+	Watts float64   `json:"watts"`
+}
+
+// MeterExchange is the point a new meter took over on a meter point
+type MeterExchange struct {
+	FuelType  string    `json:"fuelType"`
+	Date      time.Time `json:"date"` // Start of the first reading from the new meter
+	OldSerial string    `json:"oldSerial"`
+	NewSerial string    `json:"newSerial"`
+}
+
+// Gap kinds found when assessing data coverage
+const (
+	GapMissing = "missing" // No reading for the slots
+	GapZero    = "zero"    // A long run of zero readings
+)
+
+// DataCoverage describes how completely a meter's readings cover the analysis period
+type DataCoverage struct {
+	FuelType        string    `json:"fuelType"`           // electricity, export or gas
+	Property        string    `json:"property,omitempty"` // Set when the result covers several properties
+	PeriodStart     time.Time `json:"periodStart"`
+	PeriodEnd       time.Time `json:"periodEnd"`
+	SlotMinutes     int       `json:"slotMinutes"`     // Length of each reading interval
+	ExpectedSlots   int       `json:"expectedSlots"`   // Slots in the period
+	PresentSlots    int       `json:"presentSlots"`    // Slots with a reading
+	MissingSlots    int       `json:"missingSlots"`    // Slots with no reading
+	DuplicateSlots  int       `json:"duplicateSlots"`  // Repeated readings for a slot (ignored in averages)
+	ZeroSlots       int       `json:"zeroSlots"`       // Readings of exactly zero
+	DaysCovered     float64   `json:"daysCovered"`     // Days' worth of readings that averages are based on
+	CoveragePercent float64   `json:"coveragePercent"` // Present slots as a share of the period
+	FirstReading    time.Time `json:"firstReading,omitempty"`
+	LastReading     time.Time `json:"lastReading,omitempty"` // End of the most recent reading
+	GapCount        int       `json:"gapCount"`              // All gaps found, including any not listed
+	Gaps            []DataGap `json:"gaps,omitempty"`        // The longest gaps, in date order
+}
+
+// DataGap is a run of missing or zero readings
+type DataGap struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Slots int       `json:"slots"`
+	Kind  string    `json:"kind"` // missing or zero
 }
 
 // Anomaly represents a detected anomaly in consumption or cost
 type Anomaly struct {
 	Date             time.Time    `json:"date"`
 	FuelType         string       `json:"fuelType"` // electricity, gas, export
-	Type             string       `json:"type"`     // consumption_spike, cost_spike, low_usage
+	Type             string       `json:"type"`     // consumption_spike, interval_spike, low_usage
 	Description      string       `json:"description"`
 	ActualValue      float64      `json:"actualValue"`
-	ExpectedValue    float64      `json:"expectedValue"`
+	ExpectedValue    float64      `json:"expectedValue"` // From the model that flagged it most strongly
 	DeviationPercent float64      `json:"deviationPercent"`
+	Model            string       `json:"model"`            // Detection model that flagged it most strongly
+	Models           []string     `json:"models,omitempty"` // Every model that flagged it
+	Score            float64      `json:"score"`            // Robust z-score against Model's baseline
 	Weather          *WeatherData `json:"weather,omitempty"` // Optional weather context
 }
 
@@ -180,13 +426,97 @@ type TariffChange struct {
 	ImpactDescription string    `json:"impactDescription"`
 }
 
+// BudgetTracking compares daily net cost with the configured budget
+type BudgetTracking struct {
+	TargetDailySpend        float64       `json:"targetDailySpend,omitempty"` // Pounds a day, when configured
+	MonthlyBudget           float64       `json:"monthlyBudget,omitempty"`    // Pounds a month, when configured
+	CarryOver               bool          `json:"carryOver"`                  // Unspent or overspent budget moves to the next month
+	DaysTracked             int           `json:"daysTracked"`                // Days with complete readings in the period
+	DaysOverBudget          int           `json:"daysOverBudget"`             // Of those, days over the daily target
+	AvgDailySpend           float64       `json:"avgDailySpend"`              // Pounds, over the days tracked
+	MonthPace               float64       `json:"monthPace"`                  // Pounds a day spent so far this month
+	ProjectedMonthSpend     float64       `json:"projectedMonthSpend"`        // Pounds this month at its pace so far
+	ProjectedOvershoot      float64       `json:"projectedOvershoot"`         // Pounds over this month's budget (negative when under)
+	RemainingDays           int           `json:"remainingDays"`              // Days from today to the end of the month
+	RemainingDailyAllowance float64       `json:"remainingDailyAllowance"`    // Pounds a day left for the rest of the month
+	Months                  []BudgetMonth `json:"months"`                     // Oldest first, ending with this month
+}
+
+// BudgetMonth is one calendar month of budget tracking
+type BudgetMonth struct {
+	Month       time.Time `json:"month"`       // First of the month, UK time
+	Days        int       `json:"days"`        // Days tracked
+	DaysInMonth int       `json:"daysInMonth"`
+	Budget      float64   `json:"budget"`    // Pounds for the days tracked (the whole month for this month), before carry-over
+	CarriedIn   float64   `json:"carriedIn"` // Pounds carried over from earlier months (negative after overspending)
+	Spent       float64   `json:"spent"`     // Pounds, net of export
+	DaysOver    int       `json:"daysOver"`  // Days over the daily target
+}
+
+// TrendSnapshot holds the headline figures of one analysis. Its JSON names
+// match AnalysisResult's, so stored analyses decode straight into it.
+type TrendSnapshot struct {
+	GeneratedAt            time.Time `json:"generatedAt"`
+	AnalysisPeriodStart    time.Time `json:"analysisPeriodStart"`
+	AnalysisPeriodEnd      time.Time `json:"analysisPeriodEnd"`
+	AvgDailyElectricity    float64   `json:"avgDailyElectricity"`    // kWh (import)
+	AvgDailyGas            float64   `json:"avgDailyGas"`            // kWh
+	AvgDailyExport         float64   `json:"avgDailyExport"`         // kWh
+	AvgDailyCostTotal      float64   `json:"avgDailyCostTotal"`      // Pounds (net)
+	AvgDailyEarningsExport float64   `json:"avgDailyEarningsExport"` // Pounds
+	RecommendedDirectDebit float64   `json:"recommendedDirectDebit"` // Pounds per month
+}
+
+// TrendReport compares this analysis with earlier ones kept in local storage
+type TrendReport struct {
+	Runs        int               `json:"runs"`        // Earlier stored analyses
+	Current     TrendSnapshot     `json:"current"`
+	Comparisons []TrendComparison `json:"comparisons"` // Last report, last month and same period last year, where stored
+	History     []TrendSnapshot   `json:"history"`     // Latest analysis each day, oldest first, ending with this one
+}
+
+// TrendComparison is an earlier analysis to compare the current one with
+type TrendComparison struct {
+	Label    string        `json:"label"` // e.g. "Last report"
+	Previous TrendSnapshot `json:"previous"`
+}
+
 // Insight represents an actionable recommendation
 type Insight struct {
-	Category    string `json:"category"` // payment, usage, tariff, seasonal
+	Category    string `json:"category"` // payment, usage, tariff, seasonal, data
 	Priority    string `json:"priority"` // high, medium, low
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Action      string `json:"action"`
+	Confidence  string `json:"confidence,omitempty"` // medium or low when based on incomplete meter data
+}
+
+// TariffComparison ranks alternative products by what the analysis period's
+// actual usage would have cost under each of them
+type TariffComparison struct {
+	GeneratedAt time.Time        `json:"generatedAt"`
+	PeriodStart time.Time        `json:"periodStart"`
+	PeriodEnd   time.Time        `json:"periodEnd"`
+	Region      string           `json:"region"`
+	ImportKWh   float64          `json:"importKWh"`
+	ExportKWh   float64          `json:"exportKWh"`
+	Scenarios   []TariffScenario `json:"scenarios"` // Cheapest first; unpriced scenarios last
+}
+
+// TariffScenario is the repriced cost of the analysis period under one tariff
+type TariffScenario struct {
+	Name            string  `json:"name"`
+	Current         bool    `json:"current"`                 // The tariff actually billed
+	ImportProduct   string  `json:"importProduct,omitempty"` // Product code
+	ExportProduct   string  `json:"exportProduct,omitempty"` // Product code (empty keeps current export earnings)
+	ImportCost      float64 `json:"importCost"`              // Pounds
+	StandingCharges float64 `json:"standingCharges"`         // Pounds
+	ExportEarnings  float64 `json:"exportEarnings"`          // Pounds
+	NetCost         float64 `json:"netCost"`                 // Import + standing - export, pounds
+	Delta           float64 `json:"delta"`                   // Net cost minus current net cost (negative = saving)
+	ImportCoverage  float64 `json:"importCoverage"`          // Percentage of import slots with a rate
+	ExportCoverage  float64 `json:"exportCoverage"`          // Percentage of export slots with a rate
+	Error           string  `json:"error,omitempty"`         // Why the scenario could not be priced
 }
 
 // GraphQL response structures for API calls
@@ -224,6 +554,7 @@ type AccountDetailsResponse struct {
 						Tariff    struct {
 							DisplayName    string  `json:"displayName"`
 							FullName       string  `json:"fullName"`
+							TariffCode     string  `json:"tariffCode"`
 							StandingCharge float64 `json:"standingCharge"`
 							UnitRate       float64 `json:"unitRate"`
 							DayRate        float64 `json:"dayRate"`
@@ -234,8 +565,9 @@ type AccountDetailsResponse struct {
 				GasMeterPoints []struct {
 					MPRN   string `json:"mprn"`
 					Meters []struct {
-						SerialNumber string `json:"serialNumber"`
-						SmartDevices []struct {
+						SerialNumber     string `json:"serialNumber"`
+						ConsumptionUnits string `json:"consumptionUnits"`
+						SmartDevices     []struct {
 							DeviceID string `json:"deviceId"`
 						} `json:"smartDevices"`
 					} `json:"meters"`
@@ -245,6 +577,7 @@ type AccountDetailsResponse struct {
 						Tariff    struct {
 							DisplayName    string  `json:"displayName"`
 							FullName       string  `json:"fullName"`
+							TariffCode     string  `json:"tariffCode"`
 							StandingCharge float64 `json:"standingCharge"`
 							UnitRate       float64 `json:"unitRate"`
 							DayRate        float64 `json:"dayRate"`
@@ -303,6 +636,94 @@ type GraphQLError struct {
 	Path    []string `json:"path,omitempty"`
 }
 
+// AccountBillsResponse represents the account bills GraphQL response
+type AccountBillsResponse struct {
+	Data struct {
+		Account struct {
+			Bills struct {
+				Edges []struct {
+					Node struct {
+						ID             string `json:"id"`
+						BillType       string `json:"billType"`
+						IssuedDate     string `json:"issuedDate"`
+						FromDate       string `json:"fromDate"`
+						ToDate         string `json:"toDate"`
+						OpeningBalance int    `json:"openingBalance"` // Pence
+						ClosingBalance int    `json:"closingBalance"` // Pence
+						TotalCharges   struct {
+							GrossTotal int `json:"grossTotal"` // Pence
+						} `json:"totalCharges"`
+						TotalCredits struct {
+							GrossTotal int `json:"grossTotal"` // Pence
+						} `json:"totalCredits"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"bills"`
+		} `json:"account"`
+	} `json:"data"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// AccountTransactionsResponse represents the account transactions GraphQL response
+type AccountTransactionsResponse struct {
+	Data struct {
+		Account struct {
+			Transactions struct {
+				Edges []struct {
+					Node struct {
+						Typename   string `json:"__typename"` // Charge, Credit, Payment or Refund
+						ID         string `json:"id"`
+						PostedDate string `json:"postedDate"`
+						Title      string `json:"title"`
+						Amounts    struct {
+							Gross int `json:"gross"` // Pence
+						} `json:"amounts"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"transactions"`
+		} `json:"account"`
+	} `json:"data"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// PaymentSchedulesResponse represents the payment schedules GraphQL response
+type PaymentSchedulesResponse struct {
+	Data struct {
+		Account struct {
+			PaymentSchedules struct {
+				Edges []struct {
+					Node struct {
+						ID                         string  `json:"id"`
+						ValidFrom                  string  `json:"validFrom"`
+						ValidTo                    *string `json:"validTo"`
+						PaymentAmount              int     `json:"paymentAmount"` // Pence
+						PaymentDay                 int     `json:"paymentDay"`
+						PaymentFrequency           string  `json:"paymentFrequency"`
+						PaymentFrequencyMultiplier int     `json:"paymentFrequencyMultiplier"`
+						IsVariablePaymentAmount    bool    `json:"isVariablePaymentAmount"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"paymentSchedules"`
+		} `json:"account"`
+	} `json:"data"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// CompletedDispatchesResponse represents the completed dispatches GraphQL response
+type CompletedDispatchesResponse struct {
+	Data struct {
+		CompletedDispatches []struct {
+			Start string `json:"start"`
+			End   string `json:"end"`
+			Delta string `json:"delta"` // Decimal kWh, encoded as a string
+			Meta  struct {
+				Source string `json:"source"`
+			} `json:"meta"`
+		} `json:"completedDispatches"`
+	} `json:"data"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
 // RESTConsumptionResponse represents the REST API consumption response
 type RESTConsumptionResponse struct {
 	Count    int    `json:"count"`
@@ -351,10 +772,10 @@ type OpenMeteoResponse struct {
 	Longitude float64 `json:"longitude"`
 	Daily     struct {
 		Time            []string  `json:"time"`
-		TempMax         []float64 `json:"temperature_2m_max"`
-		TempMin         []float64 `json:"temperature_2m_min"`
-		TempMean        []float64 `json:"temperature_2m_mean"`
-		Precipitation   []float64 `json:"precipitation_sum"`
+		TempMax         []*float64 `json:"temperature_2m_max"` // null for days not yet in the archive
+		TempMin         []*float64 `json:"temperature_2m_min"`
+		TempMean        []*float64 `json:"temperature_2m_mean"`
+		Precipitation   []*float64 `json:"precipitation_sum"`
 		WeatherCode     []int     `json:"weather_code"`
 	} `json:"daily"`
 }
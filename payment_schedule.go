@@ -0,0 +1,90 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Direct Debit sources, recorded so reports can explain where the amount came from
+const (
+	DirectDebitSourceAccount = "account"
+	DirectDebitSourceConfig  = "config"
+)
+
+// directDebitMismatchTolerance is the difference (pounds) ignored when comparing
+// the configured Direct Debit with the account's schedule
+const directDebitMismatchTolerance = 1.0
+
+// MonthlyAmount converts the collection amount to a monthly equivalent in pounds,
+// returning 0 when the frequency isn't recognised
+func (s *PaymentSchedule) MonthlyAmount() float64 {
+	multiplier := float64(s.FrequencyMultiplier)
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	switch strings.ToLower(s.Frequency) {
+	case "weekly":
+		return s.Amount * 52 / 12 / multiplier
+	case "monthly":
+		return s.Amount / multiplier
+	case "quarterly":
+		return s.Amount / 3 / multiplier
+	case "yearly", "annually":
+		return s.Amount / 12 / multiplier
+	}
+	return 0
+}
+
+// Describe returns a human-readable summary such as "£120.00 monthly on day 15"
+func (s *PaymentSchedule) Describe() string {
+	if s.IsVariable {
+		return "variable amount based on each bill"
+	}
+
+	frequency := strings.ToLower(s.Frequency)
+	if s.FrequencyMultiplier > 1 {
+		frequency = fmt.Sprintf("every %d %ss", s.FrequencyMultiplier, strings.TrimSuffix(frequency, "ly"))
+	}
+
+	description := fmt.Sprintf("%s %s", FormatCurrency(s.Amount), frequency)
+	if s.PaymentDay > 0 && strings.EqualFold(s.Frequency, "monthly") {
+		description += fmt.Sprintf(" on day %d", s.PaymentDay)
+	}
+	return description
+}
+
+// nextPaymentDate returns the next collection on or after t for monthly
+// schedules, clamping the payment day to the length of the month
+func (s *PaymentSchedule) nextPaymentDate(t time.Time) time.Time {
+	if s.PaymentDay < 1 || !strings.EqualFold(s.Frequency, "monthly") {
+		return time.Time{}
+	}
+
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for i := 0; i < 2; i++ {
+		month := time.Date(today.Year(), today.Month()+time.Month(i), 1, 0, 0, 0, 0, t.Location())
+		day := s.PaymentDay
+		if last := month.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		if candidate := month.AddDate(0, 0, day-1); !candidate.Before(today) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Label returns the property's address, or its ID when no address is recorded
+func (p Property) Label() string {
+	if address := strings.TrimSpace(p.Address); address != "" {
+		return address
+	}
+	return "Property " + p.ID
+}
+
+// matchesProperty reports whether a selector from config names the property,
+// either by exact ID or as a case-insensitive part of its address
+func matchesProperty(property Property, selector string) bool {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return false
+	}
+	return property.ID == selector || containsIgnoreCase(property.Address, selector)
+}
+
+// SelectProperties returns the account's properties matched by any selector, in
+// account order, along with any selectors that matched nothing. Every property
+// is selected when there are no selectors.
+func SelectProperties(properties []Property, selectors []string) ([]Property, []string) {
+	if len(selectors) == 0 {
+		return properties, nil
+	}
+
+	matched := make(map[string]bool)
+	var selected []Property
+	for _, property := range properties {
+		for _, selector := range selectors {
+			if matchesProperty(property, selector) {
+				matched[selector] = true
+				selected = append(selected, property)
+				break
+			}
+		}
+	}
+
+	var unmatched []string
+	for _, selector := range selectors {
+		if !matched[selector] {
+			unmatched = append(unmatched, selector)
+		}
+	}
+
+	return selected, unmatched
+}
+
+// RollupCollectedData combines per-property data into account-level data.
+// Consumption is pooled, standing charges are summed per day, dispatches are
+// kept once per start time, and per-meter details such as agreements are left
+// to the individual properties.
+func RollupCollectedData(properties []*CollectedData) *CollectedData {
+	rollup := &CollectedData{
+		Properties: properties,
+	}
+	if len(properties) == 0 {
+		return rollup
+	}
+
+	rollup.Account = properties[0].Account
+	rollup.FetchedAt = properties[0].FetchedAt
+	rollup.Region = properties[0].Region
+	rollup.RegionSource = properties[0].RegionSource
+	rollup.PricingModel = properties[0].PricingModel
+
+	var electricityStanding, gasStanding [][]DailyStandingCharge
+	gasUnitsMixed := false
+	for _, property := range properties {
+		rollup.ElectricityConsumption = append(rollup.ElectricityConsumption, property.ElectricityConsumption...)
+		rollup.ElectricityExport = append(rollup.ElectricityExport, property.ElectricityExport...)
+		rollup.GasConsumption = append(rollup.GasConsumption, property.GasConsumption...)
+		rollup.Dispatches = mergeDispatches(rollup.Dispatches, property.Dispatches)
+		rollup.DispatchKWh += property.DispatchKWh
+		rollup.DispatchSavings += property.DispatchSavings
+		rollup.MeterSegments = append(rollup.MeterSegments, property.MeterSegments...)
+		rollup.MeterExchanges = append(rollup.MeterExchanges, property.MeterExchanges...)
+		electricityStanding = append(electricityStanding, property.ElectricityStandingCharges)
+		gasStanding = append(gasStanding, property.GasStandingCharges)
+
+		// Region and pricing only describe the rollup when every property shares them
+		if property.Region != rollup.Region {
+			rollup.Region, rollup.RegionSource = "", ""
+		}
+		if property.PricingModel != rollup.PricingModel {
+			rollup.PricingModel = ""
+		}
+		if len(property.GasConsumption) > 0 && !gasUnitsMixed {
+			if rollup.GasUnit == "" {
+				rollup.GasUnit, rollup.GasUnitSource = property.GasUnit, property.GasUnitSource
+			} else if property.GasUnit != rollup.GasUnit {
+				rollup.GasUnit, rollup.GasUnitSource = "", ""
+				gasUnitsMixed = true
+			}
+		}
+	}
+
+	sortConsumption(rollup.ElectricityConsumption)
+	sortConsumption(rollup.ElectricityExport)
+	sortConsumption(rollup.GasConsumption)
+	rollup.ElectricityStandingCharges = sumStandingCharges(electricityStanding...)
+	rollup.GasStandingCharges = sumStandingCharges(gasStanding...)
+
+	return rollup
+}
+
+// sortConsumption orders consumption records by interval start
+func sortConsumption(consumptions []Consumption) {
+	sort.SliceStable(consumptions, func(i, j int) bool {
+		return consumptions[i].StartAt.Before(consumptions[j].StartAt)
+	})
+}
+
+// sumStandingCharges adds several standing charge series together day by day
+func sumStandingCharges(chargeSets ...[]DailyStandingCharge) []DailyStandingCharge {
+	byDay := make(map[string]*DailyStandingCharge)
+	var days []string
+	for _, charges := range chargeSets {
+		for _, charge := range charges {
+			key := londonDate(charge.Date)
+			if existing, ok := byDay[key]; ok {
+				existing.Charge += charge.Charge
+				continue
+			}
+			byDay[key] = &DailyStandingCharge{Date: charge.Date, Charge: charge.Charge}
+			days = append(days, key)
+		}
+	}
+
+	sort.Strings(days)
+	summed := make([]DailyStandingCharge, 0, len(days))
+	for _, day := range days {
+		summed = append(summed, *byDay[day])
+	}
+	return summed
+}
+
+// PropertyLabel returns the address (or ID) of the property the result covers
+func (r *AnalysisResult) PropertyLabel() string {
+	return Property{ID: r.PropertyID, Address: r.PropertyAddress}.Label()
+}
+
+// currentTariffNames lists the tariff in force on each of a result's meters
+func currentTariffNames(result *AnalysisResult) []string {
+	var names []string
+	if tariff := currentTariff(result.ElectricityAgreements); tariff != nil {
+		names = append(names, "Electricity: "+tariff.DisplayName)
+	}
+	if tariff := currentTariff(result.ElectricityExportAgreements); tariff != nil {
+		names = append(names, "Export: "+tariff.DisplayName)
+	}
+	if tariff := currentTariff(result.GasAgreements); tariff != nil {
+		names = append(names, "Gas: "+tariff.DisplayName)
+	}
+	return names
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"strings"
+)
+
+// DefaultRegion is used when no region can be determined (London)
+const DefaultRegion = "C"
+
+// Region sources, recorded so reports can explain where the region came from
+const (
+	RegionSourceConfig      = "config"
+	RegionSourceTariffCode  = "tariff code"
+	RegionSourceDistributor = "MPAN distributor ID"
+	RegionSourceDefault     = "default"
+)
+
+// regionNames maps grid supply point (GSP) region letters to their areas
+var regionNames = map[string]string{
+	"A": "Eastern England",
+	"B": "East Midlands",
+	"C": "London",
+	"D": "Merseyside and Northern Wales",
+	"E": "West Midlands",
+	"F": "North Eastern England",
+	"G": "North Western England",
+	"H": "Southern England",
+	"J": "South Eastern England",
+	"K": "Southern Wales",
+	"L": "South Western England",
+	"M": "Yorkshire",
+	"N": "Southern Scotland",
+	"P": "Northern Scotland",
+}
+
+// distributorRegions maps the MPAN distributor ID (first two digits) to a GSP region
+var distributorRegions = map[string]string{
+	"10": "A",
+	"11": "B",
+	"12": "C",
+	"13": "D",
+	"14": "E",
+	"15": "F",
+	"16": "G",
+	"17": "P",
+	"18": "N",
+	"19": "J",
+	"20": "H",
+	"21": "K",
+	"22": "L",
+	"23": "M",
+}
+
+// IsValidRegion reports whether region is a known GSP region letter
+func IsValidRegion(region string) bool {
+	_, ok := regionNames[region]
+	return ok
+}
+
+// RegionName returns the human-readable area for a GSP region letter
+func RegionName(region string) string {
+	if name, ok := regionNames[region]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// ResolveRegion determines the GSP region for a meter point, preferring the
+// configured override, then the agreement's tariff code, then the MPAN
+func ResolveRegion(override, mpan string, agreements []Agreement) (string, string) {
+	if override != "" {
+		return override, RegionSourceConfig
+	}
+
+	// Prefer the agreement active now, falling back to any agreement with a code
+	if tariff := findActiveTariff(now(), agreements); tariff != nil {
+		if region := regionFromTariffCode(tariff.TariffCode); region != "" {
+			return region, RegionSourceTariffCode
+		}
+	}
+	for i := len(agreements) - 1; i >= 0; i-- {
+		if region := regionFromTariffCode(agreements[i].Tariff.TariffCode); region != "" {
+			return region, RegionSourceTariffCode
+		}
+	}
+
+	if region := regionFromMPAN(mpan); region != "" {
+		return region, RegionSourceDistributor
+	}
+
+	return DefaultRegion, RegionSourceDefault
+}
+
+// regionFromTariffCode extracts the region letter from a tariff code
+// such as E-1R-VAR-22-11-01-C
+func regionFromTariffCode(tariffCode string) string {
+	idx := strings.LastIndex(tariffCode, "-")
+	if idx < 0 || idx == len(tariffCode)-1 {
+		return ""
+	}
+
+	region := strings.ToUpper(tariffCode[idx+1:])
+	if !IsValidRegion(region) {
+		return ""
+	}
+	return region
+}
+
+// regionFromMPAN derives the region from the distributor ID at the start of an MPAN core
+func regionFromMPAN(mpan string) string {
+	mpan = strings.TrimSpace(mpan)
+	if len(mpan) < 2 {
+		return ""
+	}
+	return distributorRegions[mpan[:2]]
+}
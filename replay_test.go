@@ -0,0 +1,97 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "Rewrite golden reports from the current output")
+
+// TestReplayReport replays the recorded session in testdata/replay and checks
+// the Markdown report matches the golden copy line for line
+func TestReplayReport(t *testing.T) {
+	dir := filepath.Join("testdata", "replay")
+
+	config, err := LoadConfig(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	config.FixtureMode = FixtureModeReplay
+	config.FixturePath = filepath.Join(dir, "session")
+	config.StoragePath = t.TempDir()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+
+	// The report names the build, which differs between checkouts, and
+	// replay pins the clock for the rest of the run
+	savedVersion, savedNow := version, now
+	defer func() { version, now = savedVersion, savedNow }()
+	version = "replay"
+
+	logger := NewLogger(false)
+	transport, err := setupFixtures(config, logger)
+	if err != nil {
+		t.Fatalf("failed to set up fixtures: %v", err)
+	}
+
+	result, err := runAccount(context.Background(), config, transport, logger)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.md")
+	if err := writeReport(result, outputPath, false, logger); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	got, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+
+	goldenPath := filepath.Join(dir, "report.md")
+	if *update {
+		if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+			t.Fatalf("failed to update golden report: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("failed to read golden report: %v", err)
+	}
+
+	gotLines := strings.Split(string(got), "\n")
+	wantLines := strings.Split(string(want), "\n")
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		var gotLine, wantLine string
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if gotLine != wantLine {
+			t.Fatalf("report differs from %s at line %d:\n got: %q\nwant: %q\n(run go test -run TestReplayReport -update if the change is intended)",
+				goldenPath, i+1, gotLine, wantLine)
+		}
+	}
+}
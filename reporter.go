@@ -18,9 +18,7 @@ import (
 	"io"
 	"math"
 	"os"
-	"sort"
 	"strings"
-	"time"
 )
 
 // Reporter generates markdown reports from analysis results
@@ -54,12 +52,20 @@ func (r *Reporter) GenerateReport(result *AnalysisResult, outputPath string) err
 	// Generate report content
 	r.writeHeader(writer, result)
 	r.writeSummary(writer, result)
+	r.writeTrends(writer, result)
+	r.writeBudget(writer, result)
 	r.writePaymentAnalysis(writer, result)
+	r.writeStatementHistory(writer, result)
+	r.writePaymentHistory(writer, result)
 	r.writeConsumptionAnalysis(writer, result)
+	r.writeLoadProfile(writer, result)
+	r.writeHeating(writer, result)
 	r.writeExportPerformance(writer, result)
 	r.writeTariffInformation(writer, result)
+	r.writeProperties(writer, result)
 	r.writeAnomalies(writer, result)
 	r.writeTariffChanges(writer, result)
+	r.writeDataQuality(writer, result)
 	r.writeRecommendations(writer, result)
 	r.writeFooter(writer)
 
@@ -70,6 +76,235 @@ func (r *Reporter) GenerateReport(result *AnalysisResult, outputPath string) err
 	return nil
 }
 
+// GenerateTariffComparison writes a ranked markdown table of repriced tariffs
+func (r *Reporter) GenerateTariffComparison(comparison *TariffComparison, outputPath string) error {
+	r.logger.Info("Generating tariff comparison")
+
+	var w io.Writer
+	if outputPath == "" {
+		w = os.Stdout
+	} else {
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create comparison file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	fmt.Fprintf(w, "# Octopus Energy Tariff Comparison\n\n")
+	fmt.Fprintf(w, "**Generated:** %s\n\n", comparison.GeneratedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "**Period:** %s to %s\n\n",
+		comparison.PeriodStart.Format("2006-01-02"),
+		comparison.PeriodEnd.Format("2006-01-02"),
+	)
+	fmt.Fprintf(w, "**Usage:** %.1f kWh imported, %.1f kWh exported\n\n", comparison.ImportKWh, comparison.ExportKWh)
+	fmt.Fprintf(w, "**Pricing Region:** %s (%s)\n\n", comparison.Region, RegionName(comparison.Region))
+	fmt.Fprintf(w, "**octobudget version:** %s\n\n", GetVersion())
+	fmt.Fprintf(w, "---\n\n")
+
+	fmt.Fprintf(w, "## 💡 Ranked by Net Cost\n\n")
+	fmt.Fprintf(w, "| Rank | Tariff | Import | Standing Charges | Export | Net Cost | vs Current |\n")
+	fmt.Fprintf(w, "|------|--------|--------|------------------|--------|----------|------------|\n")
+
+	var partial, failed bool
+	rank := 0
+	for _, scenario := range comparison.Scenarios {
+		if scenario.Error != "" {
+			failed = true
+			continue
+		}
+		rank++
+
+		name := scenario.Name
+		if scenario.Current {
+			name = fmt.Sprintf("**%s** (current)", scenario.Name)
+		}
+		if !scenario.fullyPriced() {
+			name += " ⚠️"
+			partial = true
+		}
+
+		delta := "-"
+		if !scenario.Current {
+			if scenario.Delta < 0 {
+				delta = fmt.Sprintf("🟢 -£%.2f", -scenario.Delta)
+			} else {
+				delta = fmt.Sprintf("🔴 +£%.2f", scenario.Delta)
+			}
+		}
+
+		fmt.Fprintf(w, "| %d | %s | £%.2f | £%.2f | £%.2f | £%.2f | %s |\n",
+			rank,
+			name,
+			scenario.ImportCost,
+			scenario.StandingCharges,
+			scenario.ExportEarnings,
+			scenario.NetCost,
+			delta,
+		)
+	}
+	fmt.Fprintf(w, "\n")
+
+	if partial {
+		fmt.Fprintf(w, "⚠️ *Rates were not published for the whole period (for example, the product launched part-way through). Unpriced half-hours are excluded, so these totals are understated:*\n\n")
+		for _, scenario := range comparison.Scenarios {
+			if scenario.Error == "" && !scenario.fullyPriced() {
+				fmt.Fprintf(w, "- %s: %.1f%% of import and %.1f%% of export half-hours priced\n",
+					scenario.Name, scenario.ImportCoverage, scenario.ExportCoverage)
+			}
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	if failed {
+		fmt.Fprintf(w, "### Not Compared\n\n")
+		for _, scenario := range comparison.Scenarios {
+			if scenario.Error != "" {
+				fmt.Fprintf(w, "- **%s:** %s\n", scenario.Name, scenario.Error)
+			}
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	fmt.Fprintf(w, "*Candidates without an export product keep your current export earnings. Gas is not included.*\n\n")
+	r.writeFooter(w)
+
+	if outputPath != "" {
+		r.logger.Info("Tariff comparison saved", "path", outputPath)
+	}
+
+	return nil
+}
+
+// GenerateHouseholdReport writes a markdown report comparing several accounts
+// and totalling the household's spend
+func (r *Reporter) GenerateHouseholdReport(household *HouseholdResult, outputPath string) error {
+	r.logger.Info("Generating household report")
+
+	var w io.Writer
+	if outputPath == "" {
+		w = os.Stdout
+	} else {
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create household report file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	fmt.Fprintf(w, "# Octopus Energy Household Budget Report\n\n")
+	fmt.Fprintf(w, "**Generated:** %s\n\n", household.GeneratedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "**Accounts:** %d analysed of %d\n\n", household.Analysed(), len(household.Accounts))
+	fmt.Fprintf(w, "**octobudget version:** %s\n\n", GetVersion())
+	fmt.Fprintf(w, "---\n\n")
+
+	fmt.Fprintf(w, "## 🏡 Household Summary\n\n")
+	fmt.Fprintf(w, "| Metric | Amount |\n")
+	fmt.Fprintf(w, "|--------|--------|\n")
+	fmt.Fprintf(w, "| 💰 Net Daily Cost | %s |\n", FormatCurrency(household.AvgDailyCostTotal))
+	fmt.Fprintf(w, "| 📅 Projected Monthly Cost | %s |\n", FormatCurrency(household.ProjectedMonthlyCost))
+	fmt.Fprintf(w, "| 📆 Projected Annual Cost | %s |\n", FormatCurrency(household.AvgDailyCostTotal*365))
+	fmt.Fprintf(w, "| 🏦 Combined Balance | %s |\n", FormatCurrency(household.CurrentBalance))
+	if household.CurrentDirectDebit > 0 {
+		fmt.Fprintf(w, "| 💳 Current Direct Debits | %s |\n", FormatCurrency(household.CurrentDirectDebit))
+	}
+	fmt.Fprintf(w, "| ✅ Recommended Direct Debits | %s |\n", FormatCurrency(household.RecommendedDirectDebit))
+	fmt.Fprintf(w, "\n")
+
+	fmt.Fprintf(w, "## 👪 Cost per Account\n\n")
+	fmt.Fprintf(w, "| Account | Daily Cost | Projected Monthly | Balance | Direct Debit | Recommended | Share of Household |\n")
+	fmt.Fprintf(w, "|---------|------------|-------------------|---------|--------------|-------------|--------------------|\n")
+	for _, account := range household.Accounts {
+		result := account.Result
+		if result == nil {
+			continue
+		}
+
+		current := "-"
+		if result.CurrentDirectDebit > 0 {
+			current = FormatCurrency(result.CurrentDirectDebit)
+		}
+
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			account.Name,
+			FormatCurrency(result.AvgDailyCostTotal),
+			FormatCurrency(result.ProjectedMonthlyCost),
+			FormatCurrency(result.CurrentBalance),
+			current,
+			FormatCurrency(result.RecommendedDirectDebit),
+			FormatPercentage(household.Share(result)),
+		)
+	}
+	fmt.Fprintf(w, "| **Household** | **%s** | **%s** | **%s** | **%s** | **%s** | **100.0%%** |\n",
+		FormatCurrency(household.AvgDailyCostTotal),
+		FormatCurrency(household.ProjectedMonthlyCost),
+		FormatCurrency(household.CurrentBalance),
+		FormatCurrency(household.CurrentDirectDebit),
+		FormatCurrency(household.RecommendedDirectDebit),
+	)
+	fmt.Fprintf(w, "\n")
+
+	for _, account := range household.Accounts {
+		result := account.Result
+		if result == nil {
+			continue
+		}
+
+		fmt.Fprintf(w, "### 👤 %s\n\n", account.Name)
+		fmt.Fprintf(w, "**Account:** %s · **Period:** %s to %s (%d days)\n\n",
+			account.AccountID,
+			result.AnalysisPeriodStart.Format("2006-01-02"),
+			result.AnalysisPeriodEnd.Format("2006-01-02"),
+			result.AnalysisPeriodDays,
+		)
+		r.writeDailyCostTable(w, result)
+
+		if result.PaymentStatus != "" && result.PaymentStatus != "Unknown" {
+			fmt.Fprintf(w, "**Payment Status:** %s\n\n", result.PaymentStatus)
+		}
+		var urgent int
+		for _, insight := range result.Insights {
+			if insight.Priority == "high" {
+				fmt.Fprintf(w, "- 🔴 **%s:** %s\n", insight.Title, insight.Action)
+				urgent++
+			}
+		}
+		if urgent > 0 {
+			fmt.Fprintf(w, "\n")
+		}
+		if account.ReportPath != "" {
+			fmt.Fprintf(w, "*Full report: %s*\n\n", account.ReportPath)
+		}
+	}
+
+	var failed bool
+	for _, account := range household.Accounts {
+		if account.Error != "" {
+			failed = true
+			break
+		}
+	}
+	if failed {
+		fmt.Fprintf(w, "### Not Analysed\n\n")
+		for _, account := range household.Accounts {
+			if account.Error != "" {
+				fmt.Fprintf(w, "- **%s:** %s\n", account.Name, account.Error)
+			}
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	r.writeFooter(w)
+
+	if outputPath != "" {
+		r.logger.Info("Household report saved", "path", outputPath)
+	}
+
+	return nil
+}
+
 // writeHeader writes the report header
 func (r *Reporter) writeHeader(w io.Writer, result *AnalysisResult) {
 	fmt.Fprintf(w, "# Octopus Energy Budget Analysis Report\n\n")
@@ -79,6 +314,11 @@ func (r *Reporter) writeHeader(w io.Writer, result *AnalysisResult) {
 		result.AnalysisPeriodEnd.Format("2006-01-02"),
 		result.AnalysisPeriodDays,
 	)
+	if len(result.Properties) > 0 {
+		fmt.Fprintf(w, "**Properties:** %d (figures below are for the whole account)\n\n", len(result.Properties))
+	} else if result.PropertyAddress != "" {
+		fmt.Fprintf(w, "**Property:** %s\n\n", result.PropertyAddress)
+	}
 	fmt.Fprintf(w, "**octobudget version:** %s\n\n", GetVersion())
 	fmt.Fprintf(w, "---\n\n")
 }
@@ -102,6 +342,123 @@ func (r *Reporter) writeSummary(w io.Writer, result *AnalysisResult) {
 
 	// Average daily costs in table format
 	fmt.Fprintf(w, "### 💷 Average Daily Costs\n\n")
+	r.writeDailyCostTable(w, result)
+
+	// Projected monthly cost in badge format
+	fmt.Fprintf(w, "> **📅 Projected Monthly Cost:** %s\n\n",
+		FormatCurrency(result.ProjectedMonthlyCost),
+	)
+}
+
+// writeTrends compares this report with earlier ones kept in local storage
+func (r *Reporter) writeTrends(w io.Writer, result *AnalysisResult) {
+	trends := result.Trends
+	if trends == nil {
+		return
+	}
+	metrics := trackedMetrics(trends)
+
+	fmt.Fprintf(w, "## 🔄 Changes Since Last Report\n\n")
+	fmt.Fprintf(w, "Compared with %d earlier report(s) stored locally.\n\n", trends.Runs)
+
+	fmt.Fprintf(w, "| Metric | Now |")
+	for _, comparison := range trends.Comparisons {
+		fmt.Fprintf(w, " %s |", comparisonHeading(comparison))
+	}
+	fmt.Fprintf(w, "\n|--------|-----|")
+	for range trends.Comparisons {
+		fmt.Fprintf(w, "------|")
+	}
+	fmt.Fprintf(w, "\n")
+	for _, metric := range metrics {
+		fmt.Fprintf(w, "| %s | %s |", metric.label, metric.format(metric.value(trends.Current)))
+		for _, comparison := range trends.Comparisons {
+			fmt.Fprintf(w, " %s |", formatTrendChange(metric, trends.Current, comparison.Previous))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	fmt.Fprintf(w, "\n")
+
+	if len(trends.History) < 2 {
+		return
+	}
+
+	recent := recentSnapshots(trends.History, maxSparklinePoints)
+	fmt.Fprintf(w, "### 📈 Trend Over the Last %d Reports\n\n", len(recent))
+	fmt.Fprintf(w, "| Metric | %s to %s | Low | High |\n",
+		recent[0].GeneratedAt.In(london).Format("2 Jan 2006"),
+		recent[len(recent)-1].GeneratedAt.In(london).Format("2 Jan 2006"),
+	)
+	fmt.Fprintf(w, "|--------|-------|-----|------|\n")
+	for _, metric := range metrics {
+		values := metricValues(metric, recent)
+		low, high := values[0], values[0]
+		for _, value := range values {
+			low = math.Min(low, value)
+			high = math.Max(high, value)
+		}
+		fmt.Fprintf(w, "| %s | `%s` | %s | %s |\n", metric.label, sparkline(values), metric.format(low), metric.format(high))
+	}
+	fmt.Fprintf(w, "\n")
+	fmt.Fprintf(w, "*Each report averages its own analysis period, so trends lag changes at home by up to that period.*\n\n")
+}
+
+// writeBudget writes spend against the configured budget, this month and
+// month by month
+func (r *Reporter) writeBudget(w io.Writer, result *AnalysisResult) {
+	budget := result.Budget
+	if budget == nil {
+		return
+	}
+	current := budget.CurrentMonth()
+	month := current.Month.Format("January")
+
+	fmt.Fprintf(w, "## 🎯 Budget\n\n")
+	fmt.Fprintf(w, "| Metric | Value |\n")
+	fmt.Fprintf(w, "|--------|-------|\n")
+	fmt.Fprintf(w, "| 🎯 Daily Target | %s |\n", FormatCurrency(budget.TargetOn(current.Month)))
+	if current.CarriedIn != 0 {
+		fmt.Fprintf(w, "| 📅 %s Budget | %s (%s + %s carried over) |\n", month, FormatCurrency(current.Available()), FormatCurrency(current.Budget), FormatCurrency(current.CarriedIn))
+	} else {
+		fmt.Fprintf(w, "| 📅 %s Budget | %s |\n", month, FormatCurrency(current.Available()))
+	}
+	fmt.Fprintf(w, "| 💷 Spent So Far | %s over %d days (%s/day) |\n", FormatCurrency(current.Spent), current.Days, FormatCurrency(budget.MonthPace))
+
+	status := "under budget"
+	if budget.ProjectedOvershoot > 0 {
+		status = "over budget"
+	}
+	fmt.Fprintf(w, "| 📈 Projected for %s | %s (%s %s) |\n", month, FormatCurrency(budget.ProjectedMonthSpend), FormatCurrency(math.Abs(budget.ProjectedOvershoot)), status)
+	fmt.Fprintf(w, "| 🔴 Days Over Target | %d of %d this month, %d of %d in the period |\n", current.DaysOver, current.Days, budget.DaysOverBudget, budget.DaysTracked)
+	if remaining := budget.RemainingDays; remaining > 0 {
+		fmt.Fprintf(w, "| ➗ Left to Spend | %s/day for the remaining %d days |\n", FormatCurrency(budget.RemainingDailyAllowance), remaining)
+	}
+	fmt.Fprintf(w, "\n")
+
+	fmt.Fprintf(w, "| Month | Days | Budget | Carried In | Spent | Days Over | Remaining |\n")
+	fmt.Fprintf(w, "|-------|------|--------|------------|-------|-----------|-----------|\n")
+	for _, m := range budget.Months {
+		fmt.Fprintf(w, "| %s | %d/%d | %s | %s | %s | %d | %s |\n",
+			m.Month.Format("Jan 2006"),
+			m.Days,
+			m.DaysInMonth,
+			FormatCurrency(m.Budget),
+			FormatCurrency(m.CarriedIn),
+			FormatCurrency(m.Spent),
+			m.DaysOver,
+			FormatCurrency(m.Remaining()),
+		)
+	}
+	fmt.Fprintf(w, "\n")
+	if budget.CarryOver {
+		fmt.Fprintf(w, "*What's left of each month's budget, or overspent, carries into the next. Months the period only partly covers are budgeted for the days tracked.*\n\n")
+	} else {
+		fmt.Fprintf(w, "*Months the period only partly covers are budgeted for the days tracked. Set budget_carry_over to carry what's left into the next month.*\n\n")
+	}
+}
+
+// writeDailyCostTable writes the average daily cost breakdown table
+func (r *Reporter) writeDailyCostTable(w io.Writer, result *AnalysisResult) {
 	fmt.Fprintf(w, "| Item | Cost | Consumption |\n")
 	fmt.Fprintf(w, "|------|------|-------------|\n")
 	if result.AvgDailyCostElectricity > 0 {
@@ -122,6 +479,9 @@ func (r *Reporter) writeSummary(w io.Writer, result *AnalysisResult) {
 			result.AvgDailyGas,
 		)
 	}
+	if standing := result.AvgDailyStandingElectricity + result.AvgDailyStandingGas; standing > 0 {
+		fmt.Fprintf(w, "| 🧾 Standing Charges | %s | - |\n", FormatCurrency(standing))
+	}
 	fmt.Fprintf(w, "| **💰 Net Total** | **%s** | ",
 		FormatCurrency(result.AvgDailyCostTotal),
 	)
@@ -133,11 +493,6 @@ func (r *Reporter) writeSummary(w io.Writer, result *AnalysisResult) {
 		fmt.Fprintf(w, "**%.2f kWh** |\n", totalConsumption)
 	}
 	fmt.Fprintf(w, "\n")
-
-	// Projected monthly cost in badge format
-	fmt.Fprintf(w, "> **📅 Projected Monthly Cost:** %s\n\n",
-		FormatCurrency(result.ProjectedMonthlyCost),
-	)
 }
 
 // writePaymentAnalysis writes the payment analysis section
@@ -151,7 +506,14 @@ func (r *Reporter) writePaymentAnalysis(w io.Writer, result *AnalysisResult) {
 	fmt.Fprintf(w, "| 📊 Current Monthly Cost | %s |\n", FormatCurrency(result.ProjectedMonthlyCost))
 
 	if result.CurrentDirectDebit > 0 {
-		fmt.Fprintf(w, "| 📅 Current Direct Debit | %s |\n", FormatCurrency(result.CurrentDirectDebit))
+		source := ""
+		if result.DirectDebitSource == DirectDebitSourceAccount {
+			source = " (from your account)"
+		}
+		fmt.Fprintf(w, "| 📅 Current Direct Debit | %s%s |\n", FormatCurrency(result.CurrentDirectDebit), source)
+		if result.ScheduledDirectDebit > 0 {
+			fmt.Fprintf(w, "| ⚠️ Collected by Octopus | %s (differs from config.yaml) |\n", FormatCurrency(result.ScheduledDirectDebit))
+		}
 		fmt.Fprintf(w, "| ✅ Recommended Direct Debit | %s |\n", FormatCurrency(result.RecommendedDirectDebit))
 
 		difference := result.RecommendedDirectDebit - result.CurrentDirectDebit
@@ -166,6 +528,13 @@ func (r *Reporter) writePaymentAnalysis(w io.Writer, result *AnalysisResult) {
 		fmt.Fprintf(w, "| ✅ Recommended Direct Debit | %s |\n", FormatCurrency(result.RecommendedDirectDebit))
 	}
 
+	if schedule := result.PaymentSchedule; schedule != nil {
+		fmt.Fprintf(w, "| 🗓️ Payment Schedule | %s |\n", schedule.Describe())
+		if !schedule.NextPaymentDate.IsZero() {
+			fmt.Fprintf(w, "| ⏭️ Next Payment | %s |\n", schedule.NextPaymentDate.Format("2006-01-02"))
+		}
+	}
+
 	fmt.Fprintf(w, "\n")
 
 	// Account balance analysis
@@ -194,25 +563,100 @@ func (r *Reporter) writePaymentAnalysis(w io.Writer, result *AnalysisResult) {
 			FormatCurrency(math.Abs(result.CurrentBalance)))
 	}
 
-	// Direct Debit calculation explanation
+	r.writeForecast(w, result)
+}
+
+// writeForecast explains the Direct Debit recommendation and lists the
+// month-by-month cost and balance forecast behind it
+func (r *Reporter) writeForecast(w io.Writer, result *AnalysisResult) {
+	forecast := result.Forecast
+	if forecast == nil {
+		return
+	}
+
 	fmt.Fprintf(w, "### 📐 How the Recommendation is Calculated\n\n")
-	fmt.Fprintf(w, "The recommended Direct Debit accounts for:\n\n")
-	fmt.Fprintf(w, "- **Current usage patterns** (£%.2f/day average)\n", result.AvgDailyCostTotal)
-	fmt.Fprintf(w, "- **Seasonal variations** (winter: +40%%, spring/autumn: +20%%, summer: baseline)\n")
-	fmt.Fprintf(w, "- **10%% buffer** for unexpected increases\n")
-	fmt.Fprintf(w, "- **Year-round stability** to avoid large seasonal swings\n\n")
-
-	currentMonth := time.Now().Month()
-	if currentMonth >= 11 || currentMonth <= 2 {
-		fmt.Fprintf(w, "> 🌡️ **Winter Period:** Currently in winter months when heating usage is typically 30-50%% higher. ")
-		fmt.Fprintf(w, "The recommendation ensures you can cover peak winter costs while building modest credit in summer.\n\n")
-	} else if currentMonth >= 5 && currentMonth <= 8 {
-		fmt.Fprintf(w, "> ☀️ **Summer Period:** Currently in lower-usage summer months. ")
-		fmt.Fprintf(w, "The recommendation is set to build credit now to cover higher winter costs later.\n\n")
-	} else {
-		fmt.Fprintf(w, "> 🍂 **Transition Period:** The recommendation balances seasonal changes ")
-		fmt.Fprintf(w, "to provide stable payments year-round.\n\n")
+	fmt.Fprintf(w, "Usage and cost are forecast day by day from:\n\n")
+	for _, line := range forecastBasis(result) {
+		fmt.Fprintf(w, "- %s\n", line)
+	}
+	fmt.Fprintf(w, "\n")
+	fmt.Fprintf(w, "That's %s of costs before %s, so **%s a month** over %d payments leaves a balance of %s (rounded up to the nearest £5).",
+		FormatCurrency(forecast.CostToTarget),
+		forecast.TargetDate.Format("2 January 2006"),
+		FormatCurrency(forecast.RecommendedDirectDebit),
+		forecast.Payments,
+		FormatCurrency(forecast.TargetBalance),
+	)
+	if result.CurrentDirectDebit > 0 {
+		fmt.Fprintf(w, " On your current %s the balance would be %s.", FormatCurrency(result.CurrentDirectDebit), FormatCurrency(forecast.TargetBalanceCurrent))
+	}
+	fmt.Fprintf(w, "\n\n")
+
+	if len(forecast.Months) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "### 📈 12-Month Forecast\n\n")
+	fmt.Fprintf(w, "| Month | Degree-Days | Electricity | Gas | Cost | Balance (Current DD) | Balance (Recommended DD) |\n")
+	fmt.Fprintf(w, "|-------|-------------|-------------|-----|------|----------------------|--------------------------|\n")
+	for _, month := range forecast.Months {
+		fmt.Fprintf(w, "| %s | %.0f | %.0f kWh | %.0f kWh | %s | %s | %s |\n",
+			month.Month.Format("Jan 2006"),
+			month.DegreeDays,
+			month.ElectricityKWh,
+			month.GasKWh,
+			FormatCurrency(month.Cost),
+			FormatCurrency(month.BalanceCurrent),
+			FormatCurrency(month.BalanceRecommended),
+		)
+	}
+	fmt.Fprintf(w, "\n")
+	fmt.Fprintf(w, "*Balances are at the end of each month, after any collection. Degree-days are for a typical year, so a colder or milder winter will move the figures.*\n\n")
+}
+
+// writeStatementHistory writes the recent statements issued for the account
+func (r *Reporter) writeStatementHistory(w io.Writer, result *AnalysisResult) {
+	if len(result.Statements) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "## 🧾 Statement History\n\n")
+	fmt.Fprintf(w, "| Issued | Period | Charges | Payments & Credits | Closing Balance |\n")
+	fmt.Fprintf(w, "|--------|--------|---------|--------------------|-----------------|\n")
+	for _, statement := range result.Statements {
+		fmt.Fprintf(w, "| %s | %s to %s | %s | %s | %s |\n",
+			statement.IssuedDate.Format("2006-01-02"),
+			statement.FromDate.Format("2006-01-02"),
+			statement.ToDate.Format("2006-01-02"),
+			FormatCurrency(statement.ChargeAmount/100),
+			FormatCurrency(statement.PaymentAmount/100),
+			FormatCurrency(statement.OutstandingBalance/100),
+		)
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// writePaymentHistory writes the recent payments made to the account
+func (r *Reporter) writePaymentHistory(w io.Writer, result *AnalysisResult) {
+	if len(result.Payments) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "## 💸 Payment History\n\n")
+	fmt.Fprintf(w, "| Date | Amount | Method |\n")
+	fmt.Fprintf(w, "|------|--------|--------|\n")
+
+	var total float64
+	for _, payment := range result.Payments {
+		total += payment.Amount
+		fmt.Fprintf(w, "| %s | %s | %s |\n",
+			payment.PaymentDate.Format("2006-01-02"),
+			FormatCurrency(payment.Amount/100),
+			payment.Method,
+		)
 	}
+	fmt.Fprintf(w, "\n")
+	fmt.Fprintf(w, "**Total paid:** %s across %d payments\n\n", FormatCurrency(total/100), len(result.Payments))
 }
 
 // writeConsumptionAnalysis writes the consumption analysis section
@@ -240,8 +684,158 @@ func (r *Reporter) writeConsumptionAnalysis(w io.Writer, result *AnalysisResult)
 
 	if result.AvgDailyGas > 0 {
 		fmt.Fprintf(w, "| 🔥 Daily Gas Usage | %.2f kWh |\n", result.AvgDailyGas)
+		if result.AvgDailyGasVolume > 0 {
+			fmt.Fprintf(w, "| 🔥 Daily Gas Metered | %.3f m³ |\n", result.AvgDailyGasVolume)
+		}
+	}
+
+	fmt.Fprintf(w, "\n")
+
+	if result.AvgDailyGasVolume > 0 {
+		fmt.Fprintf(w, "*%s*\n\n", gasConversionNote(result))
+	}
+
+	r.writeBaseload(w, result)
+	r.writeMeterExchanges(w, result)
+}
+
+// writeBaseload writes the always-on load estimate and how it moved week by week
+func (r *Reporter) writeBaseload(w io.Writer, result *AnalysisResult) {
+	if result.BaseloadWatts == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "### 🔌 Baseload\n\n")
+	fmt.Fprintf(w, "Your always-on load is about **%.0f W**, estimated from each day's quietest half-hours outside off-peak and charging times. ", result.BaseloadWatts)
+	if result.BaseloadRecentWatts > 0 {
+		fmt.Fprintf(w, "Over the last two weeks it has been %.0f W, which is %.0f kWh a year", result.BaseloadRecentWatts, result.BaseloadAnnualKWh)
+	} else {
+		fmt.Fprintf(w, "That's %.0f kWh a year", result.BaseloadAnnualKWh)
+	}
+	if result.BaseloadAnnualCost > 0 {
+		fmt.Fprintf(w, ", costing about **%s a year** at current rates", FormatCurrency(result.BaseloadAnnualCost))
+	}
+	fmt.Fprintf(w, ".\n\n")
+
+	weeks := baseloadWeeks(result.BaseloadDaily)
+	if len(weeks) < 2 {
+		return
+	}
+
+	fmt.Fprintf(w, "| Week Starting | Baseload |\n")
+	fmt.Fprintf(w, "|---------------|----------|\n")
+	for _, week := range weeks {
+		fmt.Fprintf(w, "| %s | %.0f W |\n", week.Date.Format("2006-01-02"), week.Watts)
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// writeLoadProfile writes when electricity is used, how import splits across
+// rate bands and what moving peak use would save
+func (r *Reporter) writeLoadProfile(w io.Writer, result *AnalysisResult) {
+	profile := result.LoadProfile
+	if profile == nil {
+		return
 	}
 
+	fmt.Fprintf(w, "## 🕒 Time of Use\n\n")
+
+	if len(profile.Bands) > 0 {
+		fmt.Fprintf(w, "| Band | Import | Share | Avg Rate | Cost |\n")
+		fmt.Fprintf(w, "|------|--------|-------|----------|------|\n")
+		for _, usage := range profile.Bands {
+			fmt.Fprintf(w, "| %s | %.1f kWh | %.1f%% | %.2fp/kWh | %s |\n",
+				bandLabel(usage.Band),
+				usage.KWh,
+				usage.SharePercent,
+				usage.AvgRate,
+				FormatCurrency(usage.Cost),
+			)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	if profile.ShiftSaving > 0 {
+		fmt.Fprintf(w, "Moving **%.0f%%** of %s use to the %s band (about %.0f kWh a year) would save around **%s a year** at the rates you paid.\n\n",
+			profile.ShiftPercent,
+			strings.ToLower(bandLabel(profile.PeakBand)),
+			strings.ToLower(bandLabel(profile.CheapestBand)),
+			profile.ShiftKWh,
+			FormatCurrency(profile.ShiftSaving),
+		)
+	}
+
+	fmt.Fprintf(w, "### Average Use by Hour\n\n")
+	fmt.Fprintf(w, "| Hour | Weekday | Weekend |\n")
+	fmt.Fprintf(w, "|------|---------|---------|\n")
+	weekday, weekend := hourlyProfile(profile.Weekday), hourlyProfile(profile.Weekend)
+	for hour := range weekday {
+		fmt.Fprintf(w, "| %s | %.2f kWh | %.2f kWh |\n", slotLabel(hour*2), weekday[hour], weekend[hour])
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// writeHeating writes how usage follows the weather and how recent usage
+// compares with earlier usage on equal weather
+func (r *Reporter) writeHeating(w io.Writer, result *AnalysisResult) {
+	if len(result.HeatingModels) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "## 🌡️ Heating & Weather\n\n")
+	fmt.Fprintf(w, "Daily usage fitted against heating degree-days (how far each day's mean temperature fell below %.1f°C):\n\n", heatingBaseTemp)
+	fmt.Fprintf(w, "| Fuel | Baseload | Per Degree-Day | Weather Explains | Heating Share | Typical Year |\n")
+	fmt.Fprintf(w, "|------|----------|----------------|------------------|---------------|--------------|\n")
+	for _, model := range result.HeatingModels {
+		fmt.Fprintf(w, "| %s | %.1f kWh/day | %.2f kWh | %.0f%% | %.0f%% | %.0f kWh |\n",
+			fuelLabel(model.FuelType),
+			model.BaseloadKWh,
+			model.KWhPerDegreeDay,
+			model.RSquared*100,
+			model.HeatingSharePercent,
+			model.NormalisedAnnualKWh,
+		)
+	}
+	fmt.Fprintf(w, "\n")
+	fmt.Fprintf(w, "*Typical Year* is the usage the fit predicts for a typical UK year of %.0f degree-days.\n\n", typicalAnnualDegreeDays)
+
+	for _, model := range result.HeatingModels {
+		if model.Comparison != nil {
+			fmt.Fprintf(w, "- %s\n", describeComparison(model))
+		}
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// writeMeterExchanges lists meter swaps and the readings each serial supplied
+func (r *Reporter) writeMeterExchanges(w io.Writer, result *AnalysisResult) {
+	if len(result.MeterExchanges) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "### 🔁 Meter Exchanges\n\n")
+	for _, exchange := range result.MeterExchanges {
+		fmt.Fprintf(w, "- **%s:** %s replaced by %s on %s\n",
+			fuelLabel(exchange.FuelType),
+			exchange.OldSerial,
+			exchange.NewSerial,
+			exchange.Date.In(london).Format("2 January 2006 15:04"),
+		)
+	}
+	fmt.Fprintf(w, "\n")
+
+	fmt.Fprintf(w, "Readings from each meter have been combined into one history:\n\n")
+	fmt.Fprintf(w, "| Fuel | Serial | From | To | Readings |\n")
+	fmt.Fprintf(w, "|------|--------|------|----|----------|\n")
+	for _, segment := range meterExchangeSegments(result) {
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %d |\n",
+			fuelLabel(segment.FuelType),
+			segment.Serial,
+			segment.Start.In(london).Format("2006-01-02 15:04"),
+			segment.End.In(london).Format("2006-01-02 15:04"),
+			segment.Readings,
+		)
+	}
 	fmt.Fprintf(w, "\n")
 }
 
@@ -337,6 +931,26 @@ func (r *Reporter) writeTariffInformation(w io.Writer, result *AnalysisResult) {
 
 	fmt.Fprintf(w, "## 📋 Detected Tariffs\n\n")
 
+	if result.Region != "" {
+		fmt.Fprintf(w, "**Pricing Region:** %s (%s) - from %s\n\n",
+			result.Region,
+			RegionName(result.Region),
+			result.RegionSource,
+		)
+	}
+
+	if result.PricingModel != "" {
+		fmt.Fprintf(w, "**Time-of-use Model:** %s\n\n", result.PricingModel)
+	}
+
+	if result.DispatchCount > 0 {
+		fmt.Fprintf(w, "**Smart-Charge Dispatches:** %d completed, %.1f kWh repriced at off-peak, worth %s over the period\n\n",
+			result.DispatchCount,
+			result.DispatchKWh,
+			FormatCurrency(result.DispatchSavings),
+		)
+	}
+
 	// Electricity Import Tariff
 	if len(result.ElectricityAgreements) > 0 {
 		fmt.Fprintf(w, "### ⚡ Electricity Import\n\n")
@@ -465,6 +1079,72 @@ func (r *Reporter) writeTariffInformation(w io.Writer, result *AnalysisResult) {
 	}
 }
 
+// writeProperties writes a comparison table and a section for each property
+// when the account has several
+func (r *Reporter) writeProperties(w io.Writer, result *AnalysisResult) {
+	if len(result.Properties) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "## 🏠 Properties\n\n")
+	fmt.Fprintf(w, "| Property | Daily Cost | Projected Monthly | Share of Account |\n")
+	fmt.Fprintf(w, "|----------|------------|-------------------|------------------|\n")
+	for _, property := range result.Properties {
+		share := 0.0
+		if result.AvgDailyCostTotal > 0 {
+			share = property.AvgDailyCostTotal / result.AvgDailyCostTotal * 100
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			property.PropertyLabel(),
+			FormatCurrency(property.AvgDailyCostTotal),
+			FormatCurrency(property.ProjectedMonthlyCost),
+			FormatPercentage(share),
+		)
+	}
+	fmt.Fprintf(w, "\n")
+
+	for _, property := range result.Properties {
+		fmt.Fprintf(w, "### 🏠 %s\n\n", property.PropertyLabel())
+		if property.PropertyID != "" {
+			fmt.Fprintf(w, "**Property ID:** %s\n\n", property.PropertyID)
+		}
+
+		if property.AvgDailyElectricity == 0 && property.AvgDailyGas == 0 {
+			fmt.Fprintf(w, "*No consumption data available for this property.*\n\n")
+			continue
+		}
+
+		r.writeDailyCostTable(w, property)
+
+		if tariffs := currentTariffNames(property); len(tariffs) > 0 {
+			fmt.Fprintf(w, "**Tariffs:** %s\n\n", strings.Join(tariffs, " · "))
+		}
+		if property.Region != "" {
+			fmt.Fprintf(w, "**Pricing Region:** %s (%s)\n\n", property.Region, RegionName(property.Region))
+		}
+		if property.PricingModel != "" {
+			fmt.Fprintf(w, "**Time-of-use Model:** %s\n\n", property.PricingModel)
+		}
+		if property.DispatchCount > 0 {
+			fmt.Fprintf(w, "**Smart-Charge Dispatches:** %d completed, worth %s over the period\n\n",
+				property.DispatchCount,
+				FormatCurrency(property.DispatchSavings),
+			)
+		}
+		if len(property.Anomalies) > 0 {
+			fmt.Fprintf(w, "**Unusual Days:** %d detected\n\n", len(property.Anomalies))
+		}
+		for _, change := range property.TariffChanges {
+			fmt.Fprintf(w, "**Tariff Change:** %s - %s → %s on %s\n\n",
+				change.FuelType,
+				change.OldTariffName,
+				change.NewTariffName,
+				change.ChangeDate.Format("2006-01-02"),
+			)
+		}
+	}
+}
+
 // writeAnomalies writes the anomalies section (showing top 10 most significant)
 func (r *Reporter) writeAnomalies(w io.Writer, result *AnalysisResult) {
 	if len(result.Anomalies) == 0 {
@@ -478,9 +1158,7 @@ func (r *Reporter) writeAnomalies(w io.Writer, result *AnalysisResult) {
 	// Sort anomalies by deviation (most significant first)
 	sortedAnomalies := make([]Anomaly, len(result.Anomalies))
 	copy(sortedAnomalies, result.Anomalies)
-	sort.Slice(sortedAnomalies, func(i, j int) bool {
-		return math.Abs(sortedAnomalies[i].DeviationPercent) > math.Abs(sortedAnomalies[j].DeviationPercent)
-	})
+	sortAnomaliesBySignificance(sortedAnomalies)
 
 	// Limit to top 10 most significant anomalies
 	displayCount := 10
@@ -494,9 +1172,11 @@ func (r *Reporter) writeAnomalies(w io.Writer, result *AnalysisResult) {
 		fmt.Fprintf(w, "Found **%d anomalies** in your consumption data:\n\n", totalAnomalies)
 	}
 
+	fmt.Fprintf(w, "Each day is compared with what the detection models expect. *Expected* comes from the model that found it most unusual.\n\n")
+
 	// Create anomalies table
-	fmt.Fprintf(w, "| Date | Fuel | Type | Actual | Expected | Deviation | Weather |\n")
-	fmt.Fprintf(w, "|------|------|------|--------|----------|-----------|----------|\n")
+	fmt.Fprintf(w, "| Date | Fuel | Type | Actual | Expected | Deviation | Model | Weather |\n")
+	fmt.Fprintf(w, "|------|------|------|--------|----------|-----------|-------|----------|\n")
 
 	for i := 0; i < displayCount; i++ {
 		anomaly := sortedAnomalies[i]
@@ -528,9 +1208,9 @@ func (r *Reporter) writeAnomalies(w io.Writer, result *AnalysisResult) {
 			}
 		}
 
-		fmt.Fprintf(w, "| %s %s | %s | %s %s %s | %.2f kWh | %.2f kWh | %s | %s |\n",
+		fmt.Fprintf(w, "| %s %s | %s | %s %s %s | %.2f kWh | %.2f kWh | %s | %s | %s |\n",
 			typeIcon,
-			anomaly.Date.Format("2006-01-02"),
+			anomalyDateLabel(anomaly),
 			fuelIcon,
 			typeIcon,
 			direction,
@@ -538,6 +1218,7 @@ func (r *Reporter) writeAnomalies(w io.Writer, result *AnalysisResult) {
 			anomaly.ActualValue,
 			anomaly.ExpectedValue,
 			FormatPercentage(anomaly.DeviationPercent),
+			anomalyModelLabel(anomaly),
 			weather,
 		)
 	}
@@ -576,6 +1257,55 @@ func (r *Reporter) writeTariffChanges(w io.Writer, result *AnalysisResult) {
 	}
 }
 
+// writeDataQuality writes how completely the meter readings cover the period
+// and where the gaps are
+func (r *Reporter) writeDataQuality(w io.Writer, result *AnalysisResult) {
+	if len(result.DataCoverage) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "## 📶 Data Quality\n\n")
+	fmt.Fprintf(w, "**Confidence:** %s - averages are based on the days each meter has readings for.\n\n", confidenceLabel(result.DataConfidence))
+
+	fmt.Fprintf(w, "| Meter | Coverage | Days Covered | Missing Slots | Duplicates | Zero Readings | Last Reading |\n")
+	fmt.Fprintf(w, "|-------|----------|--------------|---------------|------------|---------------|--------------|\n")
+	for _, c := range result.DataCoverage {
+		fmt.Fprintf(w, "| %s | %s | %.1f of %d | %d | %d | %d | %s |\n",
+			c.Label(),
+			FormatPercentage(c.CoveragePercent),
+			c.DaysCovered,
+			result.AnalysisPeriodDays,
+			c.MissingSlots,
+			c.DuplicateSlots,
+			c.ZeroSlots,
+			formatLastReading(c),
+		)
+	}
+	fmt.Fprintf(w, "\n")
+
+	gaps := 0
+	for _, c := range result.DataCoverage {
+		if len(c.Gaps) == 0 {
+			continue
+		}
+		gaps += c.GapCount
+
+		if c.GapCount > len(c.Gaps) {
+			fmt.Fprintf(w, "**%s** - %d gaps, the longest %d:\n\n", c.Label(), c.GapCount, len(c.Gaps))
+		} else {
+			fmt.Fprintf(w, "**%s** - %d gaps:\n\n", c.Label(), c.GapCount)
+		}
+		for _, gap := range c.Gaps {
+			fmt.Fprintf(w, "- %s\n", gap.Describe())
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	if gaps == 0 {
+		fmt.Fprintf(w, "*No gaps found - every interval in the period has a reading.*\n\n")
+	}
+}
+
 // writeRecommendations writes the recommendations section
 func (r *Reporter) writeRecommendations(w io.Writer, result *AnalysisResult) {
 	if len(result.Insights) == 0 {
@@ -680,6 +1410,9 @@ func (r *Reporter) writeInsight(w io.Writer, insight Insight) {
 	fmt.Fprintf(w, "#### %s\n\n", insight.Title)
 	fmt.Fprintf(w, "%s\n\n", insight.Description)
 	fmt.Fprintf(w, "**Recommended Action:** %s\n\n", insight.Action)
+	if insight.Confidence != "" {
+		fmt.Fprintf(w, "*Confidence: %s - based on incomplete meter data*\n\n", strings.ToLower(confidenceLabel(insight.Confidence)))
+	}
 }
 
 // writeFooter writes the report footer
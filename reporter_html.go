@@ -20,8 +20,8 @@ import (
 	"io"
 	"math"
 	"os"
-	"sort"
-	"time"
+	"path/filepath"
+	"strings"
 )
 
 // HTMLReporter generates HTML reports from analysis results
@@ -55,12 +55,20 @@ func (r *HTMLReporter) GenerateHTMLReport(result *AnalysisResult, outputPath str
 	// Generate HTML report content
 	r.writeHTMLHeader(writer, result)
 	r.writeHTMLSummary(writer, result)
+	r.writeHTMLTrends(writer, result)
+	r.writeHTMLBudget(writer, result)
 	r.writeHTMLPaymentAnalysis(writer, result)
+	r.writeHTMLStatementHistory(writer, result)
+	r.writeHTMLPaymentHistory(writer, result)
 	r.writeHTMLConsumptionAnalysis(writer, result)
+	r.writeHTMLLoadProfile(writer, result)
+	r.writeHTMLHeating(writer, result)
 	r.writeHTMLExportPerformance(writer, result)
 	r.writeHTMLCharts(writer, result)
 	r.writeHTMLTariffInformation(writer, result)
+	r.writeHTMLProperties(writer, result)
 	r.writeHTMLAnomalies(writer, result)
+	r.writeHTMLDataQuality(writer, result)
 	r.writeHTMLRecommendations(writer, result)
 	r.writeHTMLFooter(writer)
 
@@ -71,13 +79,290 @@ func (r *HTMLReporter) GenerateHTMLReport(result *AnalysisResult, outputPath str
 	return nil
 }
 
-func (r *HTMLReporter) writeHTMLHeader(w io.Writer, result *AnalysisResult) {
+// GenerateHTMLHouseholdReport generates an HTML report comparing several
+// accounts and totalling the household's spend
+func (r *HTMLReporter) GenerateHTMLHouseholdReport(household *HouseholdResult, outputPath string) error {
+	r.logger.Info("Generating HTML household report")
+
+	var w io.Writer
+	if outputPath == "" {
+		w = os.Stdout
+	} else {
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create HTML household report file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	r.writeHTMLDocumentStart(w, "Octopus Energy Household Budget Report")
+
+	fmt.Fprintf(w, `        <header>
+            <h1>🏡 Octopus Energy Household Budget</h1>
+            <div class="subtitle">Generated: %s</div>
+            <div class="subtitle">%d of %d accounts analysed</div>
+            <div class="subtitle" style="opacity: 0.7; font-size: 0.9em; margin-top: 10px;">octobudget %s</div>
+        </header>
+`,
+		household.GeneratedAt.Format("Monday, 2 January 2006 at 15:04"),
+		household.Analysed(),
+		len(household.Accounts),
+		GetVersion(),
+	)
+
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>🏡 Household Summary</h2>
+            <div class="metric-grid">
+                <div class="metric-card">
+                    <div class="metric-label">Net Daily Cost</div>
+                    <div class="metric-value">%s</div>
+                    <span class="badge badge-info">All Accounts</span>
+                </div>
+                <div class="metric-card">
+                    <div class="metric-label">Projected Monthly</div>
+                    <div class="metric-value">%s</div>
+                    <span class="badge badge-info">%s a year</span>
+                </div>
+                <div class="metric-card">
+                    <div class="metric-label">Combined Balance</div>
+                    <div class="metric-value">%s</div>
+                </div>
+                <div class="metric-card">
+                    <div class="metric-label">Recommended Direct Debits</div>
+                    <div class="metric-value">%s</div>
+                    <span class="badge badge-info">Currently %s</span>
+                </div>
+            </div>
+        </div>
+`,
+		FormatCurrency(household.AvgDailyCostTotal),
+		FormatCurrency(household.ProjectedMonthlyCost),
+		FormatCurrency(household.AvgDailyCostTotal*365),
+		FormatCurrency(household.CurrentBalance),
+		FormatCurrency(household.RecommendedDirectDebit),
+		FormatCurrency(household.CurrentDirectDebit),
+	)
+
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>👪 Cost per Account</h2>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Account</th>
+                        <th>Daily Cost</th>
+                        <th>Projected Monthly</th>
+                        <th>Balance</th>
+                        <th>Direct Debit</th>
+                        <th>Recommended</th>
+                        <th>Share of Household</th>
+                    </tr>
+                </thead>
+                <tbody>
+`)
+
+	for _, account := range household.Accounts {
+		result := account.Result
+		if result == nil {
+			continue
+		}
+
+		current := "-"
+		if result.CurrentDirectDebit > 0 {
+			current = FormatCurrency(result.CurrentDirectDebit)
+		}
+
+		share := household.Share(result)
+		fmt.Fprintf(w, `
+                    <tr>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>
+                            <div class="progress-bar" style="height: 20px;">
+                                <div class="progress-fill" style="width: %.0f%%;">%s</div>
+                            </div>
+                        </td>
+                    </tr>
+`,
+			html.EscapeString(account.Name),
+			FormatCurrency(result.AvgDailyCostTotal),
+			FormatCurrency(result.ProjectedMonthlyCost),
+			FormatCurrency(result.CurrentBalance),
+			current,
+			FormatCurrency(result.RecommendedDirectDebit),
+			math.Min(share, 100),
+			FormatPercentage(share),
+		)
+	}
+
+	fmt.Fprintf(w, `
+                    <tr style="font-weight: bold; background: rgba(0, 200, 150, 0.1);">
+                        <td>Household</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>100.0%%</td>
+                    </tr>
+                </tbody>
+            </table>
+        </div>
+`,
+		FormatCurrency(household.AvgDailyCostTotal),
+		FormatCurrency(household.ProjectedMonthlyCost),
+		FormatCurrency(household.CurrentBalance),
+		FormatCurrency(household.CurrentDirectDebit),
+		FormatCurrency(household.RecommendedDirectDebit),
+	)
+
+	for _, account := range household.Accounts {
+		result := account.Result
+		if result == nil {
+			continue
+		}
+
+		fmt.Fprintf(w, `
+        <div class="card">
+            <h2>👤 %s</h2>
+            <p><strong>Account:</strong> %s · <strong>Period:</strong> %s to %s (%d days)</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Item</th>
+                        <th>Cost</th>
+                        <th>Consumption</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    <tr>
+                        <td>⚡ Electricity Import</td>
+                        <td>%s</td>
+                        <td>%.2f kWh</td>
+                    </tr>
+                    <tr>
+                        <td>☀️ Solar/Battery Export</td>
+                        <td style="color: var(--success-color)">-%s</td>
+                        <td>%.2f kWh</td>
+                    </tr>
+                    <tr>
+                        <td>🔥 Gas</td>
+                        <td>%s</td>
+                        <td>%.2f kWh</td>
+                    </tr>%s
+                    <tr style="font-weight: bold; background: rgba(0, 200, 150, 0.1);">
+                        <td>💰 Net Total</td>
+                        <td>%s</td>
+                        <td>%.2f kWh</td>
+                    </tr>
+                </tbody>
+            </table>
+`,
+			html.EscapeString(account.Name),
+			html.EscapeString(account.AccountID),
+			result.AnalysisPeriodStart.Format("2 Jan 2006"),
+			result.AnalysisPeriodEnd.Format("2 Jan 2006"),
+			result.AnalysisPeriodDays,
+			FormatCurrency(result.AvgDailyCostElectricity),
+			result.AvgDailyElectricity,
+			FormatCurrency(result.AvgDailyEarningsExport),
+			result.AvgDailyExport,
+			FormatCurrency(result.AvgDailyCostGas),
+			result.AvgDailyGas,
+			standingChargesRow(result),
+			FormatCurrency(result.AvgDailyCostTotal),
+			result.AvgDailyElectricity-result.AvgDailyExport+result.AvgDailyGas,
+		)
+
+		if result.PaymentStatus != "" && result.PaymentStatus != "Unknown" {
+			fmt.Fprintf(w, `
+            <p><strong>Payment Status:</strong> %s</p>
+`,
+				html.EscapeString(result.PaymentStatus),
+			)
+		}
+		for _, insight := range result.Insights {
+			if insight.Priority != "high" {
+				continue
+			}
+			fmt.Fprintf(w, `
+            <div class="insight-box high">
+                <div class="insight-title">%s</div>
+                <div class="insight-action">
+                    <strong>Recommended Action:</strong> %s
+                </div>
+            </div>
+`,
+				html.EscapeString(insight.Title),
+				html.EscapeString(insight.Action),
+			)
+		}
+		if account.ReportPath != "" {
+			fmt.Fprintf(w, `
+            <p><em>Full report: <a href="%s" style="color: var(--secondary-color);">%s</a></em></p>
+`,
+				html.EscapeString(filepath.Base(account.ReportPath)),
+				html.EscapeString(filepath.Base(account.ReportPath)),
+			)
+		}
+
+		fmt.Fprintf(w, `
+        </div>
+`)
+	}
+
+	var failed bool
+	for _, account := range household.Accounts {
+		if account.Error != "" {
+			failed = true
+			break
+		}
+	}
+	if failed {
+		fmt.Fprintf(w, `
+        <div class="card">
+            <h2>⚠️ Not Analysed</h2>
+            <ul>
+`)
+		for _, account := range household.Accounts {
+			if account.Error != "" {
+				fmt.Fprintf(w, `
+                <li><strong>%s:</strong> %s</li>
+`,
+					html.EscapeString(account.Name),
+					html.EscapeString(account.Error),
+				)
+			}
+		}
+		fmt.Fprintf(w, `
+            </ul>
+        </div>
+`)
+	}
+
+	r.writeHTMLFooter(w)
+
+	if outputPath != "" {
+		r.logger.Info("HTML household report saved", "path", outputPath)
+	}
+
+	return nil
+}
+
+// writeHTMLDocumentStart writes the document head and styles shared by every report
+func (r *HTMLReporter) writeHTMLDocumentStart(w io.Writer, title string) {
 	fmt.Fprintf(w, `<!DOCTYPE html>
 <html lang="en">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Octopus Energy Budget Analysis Report</title>
+    <title>%s</title>
     <style>
         :root {
             --primary-color: #FF006E;
@@ -347,12 +632,19 @@ func (r *HTMLReporter) writeHTMLHeader(w io.Writer, result *AnalysisResult) {
 </head>
 <body>
     <div class="container">
-        <header>
+`,
+		html.EscapeString(title),
+	)
+}
+
+func (r *HTMLReporter) writeHTMLHeader(w io.Writer, result *AnalysisResult) {
+	r.writeHTMLDocumentStart(w, "Octopus Energy Budget Analysis Report")
+
+	fmt.Fprintf(w, `        <header>
             <h1>⚡ Octopus Energy Budget Analysis</h1>
             <div class="subtitle">Generated: %s</div>
             <div class="subtitle">Analysis Period: %s to %s (%d days)</div>
             <div class="subtitle" style="opacity: 0.7; font-size: 0.9em; margin-top: 10px;">octobudget %s</div>
-        </header>
 `,
 		result.GeneratedAt.Format("Monday, 2 January 2006 at 15:04"),
 		result.AnalysisPeriodStart.Format("2 Jan 2006"),
@@ -360,6 +652,17 @@ func (r *HTMLReporter) writeHTMLHeader(w io.Writer, result *AnalysisResult) {
 		result.AnalysisPeriodDays,
 		GetVersion(),
 	)
+
+	if len(result.Properties) > 0 {
+		fmt.Fprintf(w, `            <div class="subtitle">%d properties - figures are for the whole account</div>
+`, len(result.Properties))
+	} else if result.PropertyAddress != "" {
+		fmt.Fprintf(w, `            <div class="subtitle">%s</div>
+`, html.EscapeString(result.PropertyAddress))
+	}
+
+	fmt.Fprintf(w, `        </header>
+`)
 }
 
 func (r *HTMLReporter) writeHTMLSummary(w io.Writer, result *AnalysisResult) {
@@ -393,7 +696,7 @@ func (r *HTMLReporter) writeHTMLSummary(w io.Writer, result *AnalysisResult) {
                 <div class="metric-card">
                     <div class="metric-label">Recommended Direct Debit</div>
                     <div class="metric-value">%s</div>
-                    <span class="badge badge-success">Seasonal Adjusted</span>
+                    <span class="badge badge-success">Degree-Day Forecast</span>
                 </div>
             </div>
             
@@ -421,7 +724,7 @@ func (r *HTMLReporter) writeHTMLSummary(w io.Writer, result *AnalysisResult) {
                         <td>🔥 Gas</td>
                         <td>%s</td>
                         <td>%.2f kWh</td>
-                    </tr>
+                    </tr>%s
                     <tr style="font-weight: bold; background: rgba(0, 200, 150, 0.1);">
                         <td>💰 Net Total</td>
                         <td>%s</td>
@@ -449,128 +752,784 @@ func (r *HTMLReporter) writeHTMLSummary(w io.Writer, result *AnalysisResult) {
 		result.AvgDailyExport,
 		FormatCurrency(result.AvgDailyCostGas),
 		result.AvgDailyGas,
+		standingChargesRow(result),
 		FormatCurrency(result.AvgDailyCostTotal),
 		result.AvgDailyElectricity-result.AvgDailyExport+result.AvgDailyGas,
 	)
 }
 
-func (r *HTMLReporter) writeHTMLPaymentAnalysis(w io.Writer, result *AnalysisResult) {
+// standingChargesRow returns the summary table's standing charges row, or
+// nothing when no standing charges were recorded
+func standingChargesRow(result *AnalysisResult) string {
+	standing := result.AvgDailyStandingElectricity + result.AvgDailyStandingGas
+	if standing <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(`
+                    <tr>
+                        <td>🧾 Standing Charges</td>
+                        <td>%s</td>
+                        <td>-</td>
+                    </tr>`, FormatCurrency(standing))
+}
+
+func (r *HTMLReporter) writeHTMLTrends(w io.Writer, result *AnalysisResult) {
+	trends := result.Trends
+	if trends == nil {
+		return
+	}
+
 	fmt.Fprintf(w, `
         <div class="card">
-            <h2>💳 Payment Analysis</h2>
+            <h2>🔄 Changes Since Last Report</h2>
+            <p>Compared with %d earlier report(s) stored locally.</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Metric</th>
+                        <th>Now</th>
+`, trends.Runs)
+	for _, comparison := range trends.Comparisons {
+		fmt.Fprintf(w, `
+                        <th>%s</th>
+`, html.EscapeString(comparisonHeading(comparison)))
+	}
+	fmt.Fprintf(w, `
+                    </tr>
+                </thead>
+                <tbody>
 `)
 
-	if result.CurrentBalance > 100 {
-		monthsOfCredit := result.CurrentBalance / result.ProjectedMonthlyCost
+	for _, metric := range trackedMetrics(trends) {
 		fmt.Fprintf(w, `
-            <div class="blockquote">
-                <h3>💵 Credit Balance Analysis</h3>
-                <p>Your account holds <strong>%s in credit</strong>, equivalent to <strong>%.1f months</strong> of current usage.</p>
-            </div>
-`,
-			FormatCurrency(result.CurrentBalance),
-			monthsOfCredit,
-		)
-
-		if result.CurrentBalance > 500 && monthsOfCredit > 6 {
-			balanceReduction := result.CurrentBalance / 12
-			optimalDD := result.ProjectedMonthlyCost - balanceReduction
-			if optimalDD < 0 {
-				optimalDD = 0
-			}
-
+                    <tr>
+                        <td>%s</td>
+                        <td>%s</td>
+`, metric.label, html.EscapeString(metric.format(metric.value(trends.Current))))
+		for _, comparison := range trends.Comparisons {
 			fmt.Fprintf(w, `
-            <h4>Options for managing your credit:</h4>
-            <ol>
-                <li><strong>Request a refund</strong> of %s (50%% of credit) and maintain current Direct Debit</li>
-                <li><strong>Reduce Direct Debit</strong> to %s/month to gradually use credit over 12 months</li>
-                <li><strong>Request full refund</strong> of %s and set new Direct Debit to %s</li>
-            </ol>
-`,
-				FormatCurrency(result.CurrentBalance/2),
-				FormatCurrency(optimalDD),
-				FormatCurrency(result.CurrentBalance),
-				FormatCurrency(result.RecommendedDirectDebit),
-			)
+                        <td>%s</td>
+`, html.EscapeString(formatTrendChange(metric, trends.Current, comparison.Previous)))
 		}
+		fmt.Fprintf(w, `
+                    </tr>
+`)
 	}
 
 	fmt.Fprintf(w, `
-            <h3>📐 How the Recommendation is Calculated</h3>
-            <p>The recommended Direct Debit of <strong>%s/month</strong> accounts for:</p>
-            <ul>
-                <li><strong>Current usage patterns</strong> (£%.2f/day average)</li>
-                <li><strong>Seasonal variations</strong> (winter: +40%%, spring/autumn: +20%%, summer: baseline)</li>
-                <li><strong>10%% buffer</strong> for unexpected increases</li>
-                <li><strong>Year-round stability</strong> to avoid large seasonal swings</li>
-            </ul>
-`,
-		FormatCurrency(result.RecommendedDirectDebit),
-		result.AvgDailyCostTotal,
-	)
+                </tbody>
+            </table>
+`)
 
-	currentMonth := time.Now().Month()
-	if currentMonth >= 11 || currentMonth <= 2 {
+	if result.TrendUsageChart != "" {
 		fmt.Fprintf(w, `
-            <div class="blockquote">
-                🌡️ <strong>Winter Period:</strong> Currently in winter months when heating usage is typically 30-50%% higher. 
-                The recommendation ensures you can cover peak winter costs while building modest credit in summer.
+            <h3>Average Daily Usage by Report</h3>
+            <div style="text-align: center; margin: 20px 0;">
+                <img src="data:image/png;base64,%s" alt="Usage Trend Chart" style="max-width: 100%%; height: auto; border-radius: 8px;">
             </div>
-`)
+`, result.TrendUsageChart)
+	}
+
+	if result.TrendCostChart != "" {
+		fmt.Fprintf(w, `
+            <h3>Average Daily Cost by Report</h3>
+            <div style="text-align: center; margin: 20px 0;">
+                <img src="data:image/png;base64,%s" alt="Cost Trend Chart" style="max-width: 100%%; height: auto; border-radius: 8px;">
+            </div>
+`, result.TrendCostChart)
+	}
+
+	fmt.Fprintf(w, `
+            <p><em>Each report averages its own analysis period, so trends lag changes at home by up to that period.</em></p>
+        </div>
+`)
+}
+
+func (r *HTMLReporter) writeHTMLBudget(w io.Writer, result *AnalysisResult) {
+	budget := result.Budget
+	if budget == nil {
+		return
+	}
+	current := budget.CurrentMonth()
+	month := current.Month.Format("January")
+
+	available := FormatCurrency(current.Available())
+	if current.CarriedIn != 0 {
+		available += fmt.Sprintf(" (%s + %s carried over)", FormatCurrency(current.Budget), FormatCurrency(current.CarriedIn))
+	}
+
+	status, badge := "under budget", "badge-success"
+	if budget.ProjectedOvershoot > 0 {
+		status, badge = "over budget", "badge-danger"
+	}
+
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>🎯 Budget</h2>
+            <table>
+                <tbody>
+                    <tr><td>Daily Target</td><td>%s</td></tr>
+                    <tr><td>%s Budget</td><td>%s</td></tr>
+                    <tr><td>Spent So Far</td><td>%s over %d days (%s/day)</td></tr>
+                    <tr><td>Projected for %s</td><td>%s <span class="badge %s">%s %s</span></td></tr>
+                    <tr><td>Days Over Target</td><td>%d of %d this month, %d of %d in the period</td></tr>
+`,
+		FormatCurrency(budget.TargetOn(current.Month)),
+		month,
+		available,
+		FormatCurrency(current.Spent),
+		current.Days,
+		FormatCurrency(budget.MonthPace),
+		month,
+		FormatCurrency(budget.ProjectedMonthSpend),
+		badge,
+		FormatCurrency(math.Abs(budget.ProjectedOvershoot)),
+		status,
+		current.DaysOver,
+		current.Days,
+		budget.DaysOverBudget,
+		budget.DaysTracked,
+	)
+	if remaining := budget.RemainingDays; remaining > 0 {
+		fmt.Fprintf(w, `
+                    <tr><td>Left to Spend</td><td>%s/day for the remaining %d days</td></tr>
+`, FormatCurrency(budget.RemainingDailyAllowance), remaining)
+	}
+
+	fmt.Fprintf(w, `
+                </tbody>
+            </table>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Month</th>
+                        <th>Days</th>
+                        <th>Budget</th>
+                        <th>Carried In</th>
+                        <th>Spent</th>
+                        <th>Days Over</th>
+                        <th>Remaining</th>
+                    </tr>
+                </thead>
+                <tbody>
+`)
+	for _, m := range budget.Months {
+		fmt.Fprintf(w, `
+                    <tr>
+                        <td>%s</td>
+                        <td>%d/%d</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%d</td>
+                        <td>%s</td>
+                    </tr>
+`,
+			m.Month.Format("Jan 2006"),
+			m.Days,
+			m.DaysInMonth,
+			FormatCurrency(m.Budget),
+			FormatCurrency(m.CarriedIn),
+			FormatCurrency(m.Spent),
+			m.DaysOver,
+			FormatCurrency(m.Remaining()),
+		)
+	}
+
+	note := "Months the period only partly covers are budgeted for the days tracked. Set budget_carry_over to carry what's left into the next month."
+	if budget.CarryOver {
+		note = "What's left of each month's budget, or overspent, carries into the next. Months the period only partly covers are budgeted for the days tracked."
+	}
+	fmt.Fprintf(w, `
+                </tbody>
+            </table>
+            <p><em>%s The daily cost chart marks the daily target.</em></p>
+        </div>
+`, note)
+}
+
+func (r *HTMLReporter) writeHTMLPaymentAnalysis(w io.Writer, result *AnalysisResult) {
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>💳 Payment Analysis</h2>
+`)
+
+	if schedule := result.PaymentSchedule; schedule != nil {
+		next := ""
+		if !schedule.NextPaymentDate.IsZero() {
+			next = fmt.Sprintf(" <span class=\"badge badge-info\">next %s</span>", schedule.NextPaymentDate.Format("2 Jan 2006"))
+		}
+		fmt.Fprintf(w, `
+            <p><strong>Direct Debit:</strong> %s%s</p>
+`,
+			html.EscapeString(schedule.Describe()),
+			next,
+		)
+	}
+
+	if result.ScheduledDirectDebit > 0 {
+		fmt.Fprintf(w, `
+            <div class="blockquote">
+                ⚠️ <strong>Configured Direct Debit doesn't match your account:</strong> config.yaml says %s per month, but Octopus is collecting %s per month.
+            </div>
+`,
+			FormatCurrency(result.CurrentDirectDebit),
+			FormatCurrency(result.ScheduledDirectDebit),
+		)
+	}
+
+	if result.CurrentBalance > 100 {
+		monthsOfCredit := result.CurrentBalance / result.ProjectedMonthlyCost
+		fmt.Fprintf(w, `
+            <div class="blockquote">
+                <h3>💵 Credit Balance Analysis</h3>
+                <p>Your account holds <strong>%s in credit</strong>, equivalent to <strong>%.1f months</strong> of current usage.</p>
+            </div>
+`,
+			FormatCurrency(result.CurrentBalance),
+			monthsOfCredit,
+		)
+
+		if result.CurrentBalance > 500 && monthsOfCredit > 6 {
+			balanceReduction := result.CurrentBalance / 12
+			optimalDD := result.ProjectedMonthlyCost - balanceReduction
+			if optimalDD < 0 {
+				optimalDD = 0
+			}
+
+			fmt.Fprintf(w, `
+            <h4>Options for managing your credit:</h4>
+            <ol>
+                <li><strong>Request a refund</strong> of %s (50%% of credit) and maintain current Direct Debit</li>
+                <li><strong>Reduce Direct Debit</strong> to %s/month to gradually use credit over 12 months</li>
+                <li><strong>Request full refund</strong> of %s and set new Direct Debit to %s</li>
+            </ol>
+`,
+				FormatCurrency(result.CurrentBalance/2),
+				FormatCurrency(optimalDD),
+				FormatCurrency(result.CurrentBalance),
+				FormatCurrency(result.RecommendedDirectDebit),
+			)
+		}
+	}
+
+	r.writeHTMLForecast(w, result)
+
+	fmt.Fprintf(w, `
+        </div>
+`)
+}
+
+func (r *HTMLReporter) writeHTMLForecast(w io.Writer, result *AnalysisResult) {
+	forecast := result.Forecast
+	if forecast == nil {
+		return
+	}
+
+	fmt.Fprintf(w, `
+            <h3>📐 How the Recommendation is Calculated</h3>
+            <p>Usage and cost are forecast day by day from:</p>
+            <ul>
+`)
+	for _, line := range forecastBasis(result) {
+		fmt.Fprintf(w, `
+                <li>%s</li>
+`, html.EscapeString(line))
+	}
+
+	current := ""
+	if result.CurrentDirectDebit > 0 {
+		current = fmt.Sprintf(" On your current %s the balance would be %s.", FormatCurrency(result.CurrentDirectDebit), FormatCurrency(forecast.TargetBalanceCurrent))
+	}
+	fmt.Fprintf(w, `
+            </ul>
+            <p>That's %s of costs before %s, so <strong>%s a month</strong> over %d payments leaves a balance of %s (rounded up to the nearest £5).%s</p>
+`,
+		FormatCurrency(forecast.CostToTarget),
+		forecast.TargetDate.Format("2 January 2006"),
+		FormatCurrency(forecast.RecommendedDirectDebit),
+		forecast.Payments,
+		FormatCurrency(forecast.TargetBalance),
+		current,
+	)
+
+	if len(forecast.Months) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, `
+            <h3>📈 12-Month Forecast</h3>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Month</th>
+                        <th>Degree-Days</th>
+                        <th>Electricity</th>
+                        <th>Gas</th>
+                        <th>Cost</th>
+                        <th>Balance (Current DD)</th>
+                        <th>Balance (Recommended DD)</th>
+                    </tr>
+                </thead>
+                <tbody>
+`)
+	for _, month := range forecast.Months {
+		fmt.Fprintf(w, `
+                    <tr>
+                        <td>%s</td>
+                        <td>%.0f</td>
+                        <td>%.0f kWh</td>
+                        <td>%.0f kWh</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                    </tr>
+`,
+			month.Month.Format("Jan 2006"),
+			month.DegreeDays,
+			month.ElectricityKWh,
+			month.GasKWh,
+			FormatCurrency(month.Cost),
+			FormatCurrency(month.BalanceCurrent),
+			FormatCurrency(month.BalanceRecommended),
+		)
+	}
+	fmt.Fprintf(w, `
+                </tbody>
+            </table>
+            <p><em>Balances are at the end of each month, after any collection. Degree-days are for a typical year, so a colder or milder winter will move the figures.</em></p>
+`)
+
+	if result.BalanceForecastChart != "" {
+		fmt.Fprintf(w, `
+            <h3>Projected Account Balance</h3>
+            <div style="text-align: center; margin: 20px 0;">
+                <img src="data:image/png;base64,%s" alt="Balance Forecast Chart" style="max-width: 100%%; height: auto; border-radius: 8px;">
+            </div>
+`, result.BalanceForecastChart)
+	}
+}
+
+func (r *HTMLReporter) writeHTMLStatementHistory(w io.Writer, result *AnalysisResult) {
+	if len(result.Statements) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>🧾 Statement History</h2>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Issued</th>
+                        <th>Period</th>
+                        <th>Charges</th>
+                        <th>Payments &amp; Credits</th>
+                        <th>Closing Balance</th>
+                    </tr>
+                </thead>
+                <tbody>
+`)
+
+	for _, statement := range result.Statements {
+		fmt.Fprintf(w, `
+                    <tr>
+                        <td>%s</td>
+                        <td>%s to %s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                    </tr>
+`,
+			statement.IssuedDate.Format("2006-01-02"),
+			statement.FromDate.Format("2006-01-02"),
+			statement.ToDate.Format("2006-01-02"),
+			FormatCurrency(statement.ChargeAmount/100),
+			FormatCurrency(statement.PaymentAmount/100),
+			FormatCurrency(statement.OutstandingBalance/100),
+		)
+	}
+
+	fmt.Fprintf(w, `
+                </tbody>
+            </table>
+        </div>
+`)
+}
+
+func (r *HTMLReporter) writeHTMLPaymentHistory(w io.Writer, result *AnalysisResult) {
+	if len(result.Payments) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>💸 Payment History</h2>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Date</th>
+                        <th>Amount</th>
+                        <th>Method</th>
+                    </tr>
+                </thead>
+                <tbody>
+`)
+
+	var total float64
+	for _, payment := range result.Payments {
+		total += payment.Amount
+		fmt.Fprintf(w, `
+                    <tr>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                    </tr>
+`,
+			payment.PaymentDate.Format("2006-01-02"),
+			FormatCurrency(payment.Amount/100),
+			html.EscapeString(payment.Method),
+		)
+	}
+
+	fmt.Fprintf(w, `
+                </tbody>
+            </table>
+            <p><strong>Total paid:</strong> %s across %d payments</p>
+        </div>
+`,
+		FormatCurrency(total/100),
+		len(result.Payments),
+	)
+}
+
+func (r *HTMLReporter) writeHTMLConsumptionAnalysis(w io.Writer, result *AnalysisResult) {
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>⚡ Consumption Analysis</h2>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Metric</th>
+                        <th>Value</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    <tr>
+                        <td>⚡ Daily Electricity Import</td>
+                        <td>%.2f kWh</td>
+                    </tr>
+`,
+		result.AvgDailyElectricity,
+	)
+
+	if result.AvgDailyExport > 0 {
+		fmt.Fprintf(w, `
+                    <tr>
+                        <td>☀️ Daily Solar/Battery Export</td>
+                        <td>%.2f kWh</td>
+                    </tr>
+                    <tr>
+                        <td>🔌 Net Electricity from Grid</td>
+                        <td>%.2f kWh</td>
+                    </tr>
+`,
+			result.AvgDailyExport,
+			result.AvgDailyElectricity-result.AvgDailyExport,
+		)
+	}
+
+	fmt.Fprintf(w, `
+                    <tr>
+                        <td>🔥 Daily Gas Usage</td>
+                        <td>%.2f kWh</td>
+                    </tr>
+`,
+		result.AvgDailyGas,
+	)
+
+	if result.AvgDailyGasVolume > 0 {
+		fmt.Fprintf(w, `
+                    <tr>
+                        <td>🔥 Daily Gas Metered</td>
+                        <td>%.3f m³</td>
+                    </tr>
+`,
+			result.AvgDailyGasVolume,
+		)
+	}
+
+	fmt.Fprintf(w, `
+                </tbody>
+            </table>
+`)
+
+	if result.AvgDailyGasVolume > 0 {
+		fmt.Fprintf(w, `
+            <p><em>%s</em></p>
+`,
+			html.EscapeString(gasConversionNote(result)),
+		)
+	}
+
+	r.writeHTMLBaseload(w, result)
+	r.writeHTMLMeterExchanges(w, result)
+
+	fmt.Fprintf(w, `
+        </div>
+`)
+}
+
+func (r *HTMLReporter) writeHTMLLoadProfile(w io.Writer, result *AnalysisResult) {
+	profile := result.LoadProfile
+	if profile == nil {
+		return
+	}
+
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>🕒 Time of Use</h2>
+`)
+
+	if len(profile.Bands) > 0 {
+		fmt.Fprintf(w, `
+            <table>
+                <thead>
+                    <tr>
+                        <th>Band</th>
+                        <th>Import</th>
+                        <th>Share</th>
+                        <th>Avg Rate</th>
+                        <th>Cost</th>
+                    </tr>
+                </thead>
+                <tbody>
+`)
+		for _, usage := range profile.Bands {
+			fmt.Fprintf(w, `
+                    <tr>
+                        <td>%s</td>
+                        <td>%.1f kWh</td>
+                        <td>%.1f%%</td>
+                        <td>%.2fp/kWh</td>
+                        <td>%s</td>
+                    </tr>
+`,
+				bandLabel(usage.Band),
+				usage.KWh,
+				usage.SharePercent,
+				usage.AvgRate,
+				FormatCurrency(usage.Cost),
+			)
+		}
+		fmt.Fprintf(w, `
+                </tbody>
+            </table>
+`)
+	}
+
+	if profile.ShiftSaving > 0 {
+		fmt.Fprintf(w, `
+            <p>Moving <strong>%.0f%%</strong> of %s use to the %s band (about %.0f kWh a year) would save around <strong>%s a year</strong> at the rates you paid.</p>
+`,
+			profile.ShiftPercent,
+			strings.ToLower(bandLabel(profile.PeakBand)),
+			strings.ToLower(bandLabel(profile.CheapestBand)),
+			profile.ShiftKWh,
+			FormatCurrency(profile.ShiftSaving),
+		)
+	}
+
+	if result.LoadProfileChart != "" {
+		fmt.Fprintf(w, `
+            <h3>Average Use by Time of Day</h3>
+            <div style="text-align: center; margin: 20px 0;">
+                <img src="data:image/png;base64,%s" alt="Load Profile Chart" style="max-width: 100%%; height: auto; border-radius: 8px;">
+            </div>
+`, result.LoadProfileChart)
+	}
+
+	fmt.Fprintf(w, `
+        </div>
+`)
+}
+
+func (r *HTMLReporter) writeHTMLHeating(w io.Writer, result *AnalysisResult) {
+	if len(result.HeatingModels) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>🌡️ Heating &amp; Weather</h2>
+            <p>Daily usage fitted against heating degree-days (how far each day's mean temperature fell below %.1f°C).</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Fuel</th>
+                        <th>Baseload</th>
+                        <th>Per Degree-Day</th>
+                        <th>Weather Explains</th>
+                        <th>Heating Share</th>
+                        <th>Typical Year</th>
+                    </tr>
+                </thead>
+                <tbody>
+`,
+		heatingBaseTemp,
+	)
+	for _, model := range result.HeatingModels {
+		fmt.Fprintf(w, `
+                    <tr>
+                        <td>%s</td>
+                        <td>%.1f kWh/day</td>
+                        <td>%.2f kWh</td>
+                        <td>%.0f%%</td>
+                        <td>%.0f%%</td>
+                        <td>%.0f kWh</td>
+                    </tr>
+`,
+			fuelLabel(model.FuelType),
+			model.BaseloadKWh,
+			model.KWhPerDegreeDay,
+			model.RSquared*100,
+			model.HeatingSharePercent,
+			model.NormalisedAnnualKWh,
+		)
+	}
+	fmt.Fprintf(w, `
+                </tbody>
+            </table>
+            <p><em>Typical Year</em> is the usage the fit predicts for a typical UK year of %.0f degree-days.</p>
+`,
+		typicalAnnualDegreeDays,
+	)
+
+	for _, model := range result.HeatingModels {
+		if model.Comparison != nil {
+			fmt.Fprintf(w, `
+            <p>%s</p>
+`, html.EscapeString(describeComparison(model)))
+		}
+	}
+
+	fmt.Fprintf(w, `
+        </div>
+`)
+}
+
+func (r *HTMLReporter) writeHTMLBaseload(w io.Writer, result *AnalysisResult) {
+	if result.BaseloadWatts == 0 {
+		return
+	}
+
+	cost := ""
+	if result.BaseloadAnnualCost > 0 {
+		cost = fmt.Sprintf(", costing about <strong>%s a year</strong> at current rates", FormatCurrency(result.BaseloadAnnualCost))
+	}
+
+	annual := fmt.Sprintf("That's %.0f kWh a year", result.BaseloadAnnualKWh)
+	if result.BaseloadRecentWatts > 0 {
+		annual = fmt.Sprintf("Over the last two weeks it has been %.0f W, which is %.0f kWh a year", result.BaseloadRecentWatts, result.BaseloadAnnualKWh)
 	}
 
 	fmt.Fprintf(w, `
-        </div>
-`)
-}
+            <h3>🔌 Baseload</h3>
+            <p>Your always-on load is about <strong>%.0f W</strong>, estimated from each day's quietest half-hours outside off-peak and charging times. %s%s.</p>
+`,
+		result.BaseloadWatts,
+		annual,
+		cost,
+	)
+
+	weeks := baseloadWeeks(result.BaseloadDaily)
+	if len(weeks) < 2 {
+		return
+	}
 
-func (r *HTMLReporter) writeHTMLConsumptionAnalysis(w io.Writer, result *AnalysisResult) {
 	fmt.Fprintf(w, `
-        <div class="card">
-            <h2>⚡ Consumption Analysis</h2>
             <table>
                 <thead>
                     <tr>
-                        <th>Metric</th>
-                        <th>Value</th>
+                        <th>Week Starting</th>
+                        <th>Baseload</th>
                     </tr>
                 </thead>
                 <tbody>
+`)
+	for _, week := range weeks {
+		fmt.Fprintf(w, `
                     <tr>
-                        <td>⚡ Daily Electricity Import</td>
-                        <td>%.2f kWh</td>
+                        <td>%s</td>
+                        <td>%.0f W</td>
                     </tr>
 `,
-		result.AvgDailyElectricity,
-	)
+			week.Date.Format("2006-01-02"),
+			week.Watts,
+		)
+	}
+	fmt.Fprintf(w, `
+                </tbody>
+            </table>
+`)
+}
 
-	if result.AvgDailyExport > 0 {
+func (r *HTMLReporter) writeHTMLMeterExchanges(w io.Writer, result *AnalysisResult) {
+	if len(result.MeterExchanges) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, `
+            <h3>🔁 Meter Exchanges</h3>
+            <ul>
+`)
+	for _, exchange := range result.MeterExchanges {
 		fmt.Fprintf(w, `
+                <li><strong>%s:</strong> %s replaced by %s on %s</li>
+`,
+			fuelLabel(exchange.FuelType),
+			html.EscapeString(exchange.OldSerial),
+			html.EscapeString(exchange.NewSerial),
+			exchange.Date.In(london).Format("2 January 2006 15:04"),
+		)
+	}
+
+	fmt.Fprintf(w, `
+            </ul>
+            <p>Readings from each meter have been combined into one history:</p>
+            <table>
+                <thead>
                     <tr>
-                        <td>☀️ Daily Solar/Battery Export</td>
-                        <td>%.2f kWh</td>
+                        <th>Fuel</th>
+                        <th>Serial</th>
+                        <th>From</th>
+                        <th>To</th>
+                        <th>Readings</th>
                     </tr>
+                </thead>
+                <tbody>
+`)
+	for _, segment := range meterExchangeSegments(result) {
+		fmt.Fprintf(w, `
                     <tr>
-                        <td>🔌 Net Electricity from Grid</td>
-                        <td>%.2f kWh</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%d</td>
                     </tr>
 `,
-			result.AvgDailyExport,
-			result.AvgDailyElectricity-result.AvgDailyExport,
+			fuelLabel(segment.FuelType),
+			html.EscapeString(segment.Serial),
+			segment.Start.In(london).Format("2006-01-02 15:04"),
+			segment.End.In(london).Format("2006-01-02 15:04"),
+			segment.Readings,
 		)
 	}
-
 	fmt.Fprintf(w, `
-                    <tr>
-                        <td>🔥 Daily Gas Usage</td>
-                        <td>%.2f kWh</td>
-                    </tr>
                 </tbody>
             </table>
-        </div>
-`,
-		result.AvgDailyGas,
-	)
+`)
 }
 
 func (r *HTMLReporter) writeHTMLExportPerformance(w io.Writer, result *AnalysisResult) {
@@ -762,6 +1721,174 @@ func (r *HTMLReporter) writeHTMLCharts(w io.Writer, result *AnalysisResult) {
 `)
 }
 
+func (r *HTMLReporter) writeHTMLProperties(w io.Writer, result *AnalysisResult) {
+	if len(result.Properties) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>🏠 Properties</h2>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Property</th>
+                        <th>Daily Cost</th>
+                        <th>Projected Monthly</th>
+                        <th>Share of Account</th>
+                    </tr>
+                </thead>
+                <tbody>
+`)
+
+	for _, property := range result.Properties {
+		share := 0.0
+		if result.AvgDailyCostTotal > 0 {
+			share = property.AvgDailyCostTotal / result.AvgDailyCostTotal * 100
+		}
+		fmt.Fprintf(w, `
+                    <tr>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                    </tr>
+`,
+			html.EscapeString(property.PropertyLabel()),
+			FormatCurrency(property.AvgDailyCostTotal),
+			FormatCurrency(property.ProjectedMonthlyCost),
+			FormatPercentage(share),
+		)
+	}
+
+	fmt.Fprintf(w, `
+                </tbody>
+            </table>
+        </div>
+`)
+
+	for _, property := range result.Properties {
+		fmt.Fprintf(w, `
+        <div class="card">
+            <h2>🏠 %s</h2>
+`,
+			html.EscapeString(property.PropertyLabel()),
+		)
+
+		if property.PropertyID != "" {
+			fmt.Fprintf(w, `
+            <p><strong>Property ID:</strong> %s</p>
+`,
+				html.EscapeString(property.PropertyID),
+			)
+		}
+
+		if property.AvgDailyElectricity == 0 && property.AvgDailyGas == 0 {
+			fmt.Fprintf(w, `
+            <p><em>No consumption data available for this property.</em></p>
+        </div>
+`)
+			continue
+		}
+
+		fmt.Fprintf(w, `
+            <table>
+                <thead>
+                    <tr>
+                        <th>Item</th>
+                        <th>Cost</th>
+                        <th>Consumption</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    <tr>
+                        <td>⚡ Electricity Import</td>
+                        <td>%s</td>
+                        <td>%.2f kWh</td>
+                    </tr>
+                    <tr>
+                        <td>☀️ Solar/Battery Export</td>
+                        <td style="color: var(--success-color)">-%s</td>
+                        <td>%.2f kWh</td>
+                    </tr>
+                    <tr>
+                        <td>🔥 Gas</td>
+                        <td>%s</td>
+                        <td>%.2f kWh</td>
+                    </tr>%s
+                    <tr style="font-weight: bold; background: rgba(0, 200, 150, 0.1);">
+                        <td>💰 Net Total</td>
+                        <td>%s</td>
+                        <td>%.2f kWh</td>
+                    </tr>
+                </tbody>
+            </table>
+`,
+			FormatCurrency(property.AvgDailyCostElectricity),
+			property.AvgDailyElectricity,
+			FormatCurrency(property.AvgDailyEarningsExport),
+			property.AvgDailyExport,
+			FormatCurrency(property.AvgDailyCostGas),
+			property.AvgDailyGas,
+			standingChargesRow(property),
+			FormatCurrency(property.AvgDailyCostTotal),
+			property.AvgDailyElectricity-property.AvgDailyExport+property.AvgDailyGas,
+		)
+
+		if tariffs := currentTariffNames(property); len(tariffs) > 0 {
+			fmt.Fprintf(w, `
+            <p><strong>Tariffs:</strong> %s</p>
+`,
+				html.EscapeString(strings.Join(tariffs, " · ")),
+			)
+		}
+		if property.Region != "" {
+			fmt.Fprintf(w, `
+            <p><strong>Pricing Region:</strong> %s (%s)</p>
+`,
+				html.EscapeString(property.Region),
+				html.EscapeString(RegionName(property.Region)),
+			)
+		}
+		if property.PricingModel != "" {
+			fmt.Fprintf(w, `
+            <p><strong>Time-of-use Model:</strong> %s</p>
+`,
+				html.EscapeString(property.PricingModel),
+			)
+		}
+		if property.DispatchCount > 0 {
+			fmt.Fprintf(w, `
+            <p><strong>Smart-Charge Dispatches:</strong> %d completed <span class="badge badge-success">worth %s</span></p>
+`,
+				property.DispatchCount,
+				FormatCurrency(property.DispatchSavings),
+			)
+		}
+		if len(property.Anomalies) > 0 {
+			fmt.Fprintf(w, `
+            <p><strong>Unusual Days:</strong> %d detected</p>
+`,
+				len(property.Anomalies),
+			)
+		}
+		for _, change := range property.TariffChanges {
+			fmt.Fprintf(w, `
+            <p><strong>Tariff Change:</strong> %s - %s → %s on %s</p>
+`,
+				html.EscapeString(change.FuelType),
+				html.EscapeString(change.OldTariffName),
+				html.EscapeString(change.NewTariffName),
+				change.ChangeDate.Format("2006-01-02"),
+			)
+		}
+
+		fmt.Fprintf(w, `
+        </div>
+`)
+	}
+}
+
 func (r *HTMLReporter) writeHTMLTariffInformation(w io.Writer, result *AnalysisResult) {
 	hasAnyTariff := len(result.ElectricityAgreements) > 0 ||
 		len(result.ElectricityExportAgreements) > 0 ||
@@ -776,6 +1903,34 @@ func (r *HTMLReporter) writeHTMLTariffInformation(w io.Writer, result *AnalysisR
             <h2>📋 Detected Tariffs</h2>
 `)
 
+	if result.Region != "" {
+		fmt.Fprintf(w, `
+            <p><strong>Pricing Region:</strong> %s (%s) <span class="badge badge-info">from %s</span></p>
+`,
+			html.EscapeString(result.Region),
+			html.EscapeString(RegionName(result.Region)),
+			html.EscapeString(result.RegionSource),
+		)
+	}
+
+	if result.PricingModel != "" {
+		fmt.Fprintf(w, `
+            <p><strong>Time-of-use Model:</strong> %s</p>
+`,
+			html.EscapeString(result.PricingModel),
+		)
+	}
+
+	if result.DispatchCount > 0 {
+		fmt.Fprintf(w, `
+            <p><strong>Smart-Charge Dispatches:</strong> %d completed, %.1f kWh repriced at off-peak <span class="badge badge-success">worth %s</span></p>
+`,
+			result.DispatchCount,
+			result.DispatchKWh,
+			FormatCurrency(result.DispatchSavings),
+		)
+	}
+
 	// Electricity Import Tariff
 	if len(result.ElectricityAgreements) > 0 {
 		fmt.Fprintf(w, `
@@ -1062,9 +2217,7 @@ func (r *HTMLReporter) writeHTMLAnomalies(w io.Writer, result *AnalysisResult) {
 	// Sort and take top 10
 	anomalies := make([]Anomaly, len(result.Anomalies))
 	copy(anomalies, result.Anomalies)
-	sort.Slice(anomalies, func(i, j int) bool {
-		return math.Abs(anomalies[i].DeviationPercent) > math.Abs(anomalies[j].DeviationPercent)
-	})
+	sortAnomaliesBySignificance(anomalies)
 	if len(anomalies) > 10 {
 		anomalies = anomalies[:10]
 	}
@@ -1083,6 +2236,7 @@ func (r *HTMLReporter) writeHTMLAnomalies(w io.Writer, result *AnalysisResult) {
                         <th>Actual</th>
                         <th>Expected</th>
                         <th>Deviation</th>
+                        <th>Model</th>
                         <th>Weather</th>
                     </tr>
                 </thead>
@@ -1099,9 +2253,12 @@ func (r *HTMLReporter) writeHTMLAnomalies(w io.Writer, result *AnalysisResult) {
 
 		typeIcon := "⚠️"
 		typeText := "spike"
-		if anomaly.Type == "low_usage" {
+		switch anomaly.Type {
+		case "low_usage":
 			typeIcon = "🔵"
 			typeText = "low usage"
+		case "interval_spike":
+			typeText = "half-hourly spike"
 		}
 
 		weatherDesc := "N/A"
@@ -1122,15 +2279,17 @@ func (r *HTMLReporter) writeHTMLAnomalies(w io.Writer, result *AnalysisResult) {
                         <td>%.2f kWh</td>
                         <td>%.1f%%</td>
                         <td>%s</td>
+                        <td>%s</td>
                     </tr>
 `,
-			anomaly.Date.Format("2006-01-02"),
+			anomalyDateLabel(anomaly),
 			fuelIcon,
 			typeIcon,
 			typeText,
 			anomaly.ActualValue,
 			anomaly.ExpectedValue,
 			anomaly.DeviationPercent,
+			html.EscapeString(anomalyModelLabel(anomaly)),
 			html.EscapeString(weatherDesc),
 		)
 	}
@@ -1142,6 +2301,111 @@ func (r *HTMLReporter) writeHTMLAnomalies(w io.Writer, result *AnalysisResult) {
 `)
 }
 
+func (r *HTMLReporter) writeHTMLDataQuality(w io.Writer, result *AnalysisResult) {
+	if len(result.DataCoverage) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, `
+        <div class="card">
+            <h2>📶 Data Quality</h2>
+            <p><strong>Confidence:</strong> %s - averages are based on the days each meter has readings for.</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Meter</th>
+                        <th>Coverage</th>
+                        <th>Days Covered</th>
+                        <th>Missing Slots</th>
+                        <th>Duplicates</th>
+                        <th>Zero Readings</th>
+                        <th>Last Reading</th>
+                    </tr>
+                </thead>
+                <tbody>
+`,
+		confidenceLabel(result.DataConfidence),
+	)
+
+	for _, c := range result.DataCoverage {
+		fmt.Fprintf(w, `
+                    <tr>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%.1f of %d</td>
+                        <td>%d</td>
+                        <td>%d</td>
+                        <td>%d</td>
+                        <td>%s</td>
+                    </tr>
+`,
+			html.EscapeString(c.Label()),
+			FormatPercentage(c.CoveragePercent),
+			c.DaysCovered,
+			result.AnalysisPeriodDays,
+			c.MissingSlots,
+			c.DuplicateSlots,
+			c.ZeroSlots,
+			formatLastReading(c),
+		)
+	}
+
+	fmt.Fprintf(w, `
+                </tbody>
+            </table>
+`)
+
+	gaps := 0
+	for _, c := range result.DataCoverage {
+		if len(c.Gaps) == 0 {
+			continue
+		}
+		gaps += c.GapCount
+
+		heading := fmt.Sprintf("%s - %d gaps", c.Label(), c.GapCount)
+		if c.GapCount > len(c.Gaps) {
+			heading += fmt.Sprintf(", the longest %d", len(c.Gaps))
+		}
+		fmt.Fprintf(w, `
+            <h3>%s</h3>
+            <ul>
+`,
+			html.EscapeString(heading),
+		)
+		for _, gap := range c.Gaps {
+			fmt.Fprintf(w, `
+                <li>%s</li>
+`,
+				html.EscapeString(gap.Describe()),
+			)
+		}
+		fmt.Fprintf(w, `
+            </ul>
+`)
+	}
+
+	if gaps == 0 {
+		fmt.Fprintf(w, `
+            <p><em>No gaps found - every interval in the period has a reading.</em></p>
+`)
+	}
+
+	fmt.Fprintf(w, `
+        </div>
+`)
+}
+
+// htmlInsightConfidence notes when an insight rests on incomplete meter data
+func htmlInsightConfidence(insight Insight) string {
+	if insight.Confidence == "" {
+		return ""
+	}
+	return fmt.Sprintf(`
+                <p><em>Confidence: %s - based on incomplete meter data</em></p>`,
+		strings.ToLower(confidenceLabel(insight.Confidence)),
+	)
+}
+
 func (r *HTMLReporter) writeHTMLRecommendations(w io.Writer, result *AnalysisResult) {
 	if len(result.Insights) == 0 {
 		return
@@ -1182,13 +2446,14 @@ func (r *HTMLReporter) writeHTMLRecommendations(w io.Writer, result *AnalysisRes
                 <p>%s</p>
                 <div class="insight-action">
                     <strong>Recommended Action:</strong> %s
-                </div>
+                </div>%s
             </div>
 `,
 				priorityClass,
 				html.EscapeString(insight.Title),
 				html.EscapeString(insight.Description),
 				html.EscapeString(insight.Action),
+				htmlInsightConfidence(insight),
 			)
 		}
 	}
@@ -1223,12 +2488,13 @@ func (r *HTMLReporter) writeHTMLRecommendations(w io.Writer, result *AnalysisRes
                 <p>%s</p>
                 <div class="insight-action">
                     <strong>Recommended Action:</strong> %s
-                </div>
+                </div>%s
             </div>
 `,
 					html.EscapeString(insight.Title),
 					html.EscapeString(insight.Description),
 					html.EscapeString(insight.Action),
+					htmlInsightConfidence(insight),
 				)
 			}
 		}
@@ -1242,12 +2508,13 @@ func (r *HTMLReporter) writeHTMLRecommendations(w io.Writer, result *AnalysisRes
                 <p>%s</p>
                 <div class="insight-action">
                     <strong>Recommended Action:</strong> %s
-                </div>
+                </div>%s
             </div>
 `,
 					html.EscapeString(insight.Title),
 					html.EscapeString(insight.Description),
 					html.EscapeString(insight.Action),
+					htmlInsightConfidence(insight),
 				)
 			}
 		}
@@ -1261,12 +2528,13 @@ func (r *HTMLReporter) writeHTMLRecommendations(w io.Writer, result *AnalysisRes
                 <p>%s</p>
                 <div class="insight-action">
                     <strong>Recommended Action:</strong> %s
-                </div>
+                </div>%s
             </div>
 `,
 					html.EscapeString(insight.Title),
 					html.EscapeString(insight.Description),
 					html.EscapeString(insight.Action),
+					htmlInsightConfidence(insight),
 				)
 			}
 		}
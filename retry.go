@@ -0,0 +1,145 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRetryAttempts is the default number of attempts made for each API request
+	DefaultRetryAttempts = 4
+
+	// retryBaseDelay is the delay before the first retry, doubled on each subsequent attempt
+	retryBaseDelay = 500 * time.Millisecond
+
+	// retryMaxDelay caps the exponential backoff delay
+	retryMaxDelay = 30 * time.Second
+
+	// retryMaxRetryAfter caps how long we are willing to honour a Retry-After header
+	retryMaxRetryAfter = 2 * time.Minute
+)
+
+// RetryPolicy retries failed API requests with jittered exponential backoff
+type RetryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	logger      *Logger
+}
+
+// NewRetryPolicy creates a retry policy with the given attempt budget
+func NewRetryPolicy(maxAttempts int, logger *Logger) *RetryPolicy {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &RetryPolicy{
+		maxAttempts: maxAttempts,
+		baseDelay:   retryBaseDelay,
+		maxDelay:    retryMaxDelay,
+		logger:      logger,
+	}
+}
+
+// Do runs fn until it succeeds, fails with a non-retryable error, the attempt
+// budget is spent, or ctx is cancelled
+func (p *RetryPolicy) Do(ctx context.Context, operation string, fn func() error) error {
+	var err error
+
+	for attempt := 1; attempt <= p.maxAttempts; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		err = fn()
+		if err == nil || ctx.Err() != nil || !isRetryableError(err) || attempt == p.maxAttempts {
+			return err
+		}
+
+		delay := p.backoff(attempt)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+			delay = apiErr.RetryAfter
+			if delay > retryMaxRetryAfter {
+				delay = retryMaxRetryAfter
+			}
+		}
+
+		p.logger.Warn("Request failed, retrying",
+			"operation", operation,
+			"attempt", attempt,
+			"max_attempts", p.maxAttempts,
+			"delay", delay.Round(time.Millisecond),
+			"error", err,
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return err
+}
+
+// backoff returns the jittered delay before the given retry attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+
+	// Use equal jitter so concurrent clients don't retry in lockstep
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isRetryableError reports whether an error is worth retrying
+func isRetryableError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.IsRetryable()
+	}
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(header http.Header) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -18,14 +18,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 )
 
 // Storage handles persistent storage of data
 type Storage struct {
-	basePath string
-	cache    *Cache
-	logger   *Logger
+	basePath      string
+	cache         *Cache
+	cacheDisabled bool
+	logger        *Logger
 }
 
 // NewStorage creates a new storage handler with caching
@@ -102,6 +104,37 @@ func (s *Storage) LoadLatestAnalysis(accountID string) (*AnalysisResult, error)
 	return &result, nil
 }
 
+// LoadAnalysisHistory reads the headline figures from every stored analysis
+// for the given account, oldest first. Files that can't be read are skipped.
+func (s *Storage) LoadAnalysisHistory(accountID string) ([]TrendSnapshot, error) {
+	pattern := filepath.Join(s.basePath, fmt.Sprintf("%s_analysis_*.json", accountID))
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, &StorageError{
+			Operation: "glob_analysis",
+			Path:      pattern,
+			Err:       err,
+		}
+	}
+
+	s.logger.LogStorageOperation("load_analysis_history", pattern)
+
+	history := make([]TrendSnapshot, 0, len(matches))
+	for _, path := range matches {
+		var snapshot TrendSnapshot
+		if err := s.loadJSON(path, &snapshot); err != nil {
+			s.logger.Warn("Skipping unreadable analysis", "path", path, "error", err)
+			continue
+		}
+		history = append(history, snapshot)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].GeneratedAt.Before(history[j].GeneratedAt)
+	})
+	return history, nil
+}
+
 // saveJSON saves data as JSON to a file
 func (s *Storage) saveJSON(path string, data interface{}) error {
 	file, err := os.Create(path)
@@ -173,13 +206,26 @@ func (s *Storage) ListStoredFiles() ([]string, error) {
 	return files, nil
 }
 
+// DisableCache makes cache loads always miss and cache saves no-ops, so every
+// request reaches the HTTP layer (used when recording or replaying fixtures)
+func (s *Storage) DisableCache() {
+	s.cacheDisabled = true
+	s.logger.Debug("API response cache disabled")
+}
+
 // SaveCache saves data to cache with a TTL (time-to-live)
 func (s *Storage) SaveCache(key string, data interface{}, ttl time.Duration) error {
+	if s.cacheDisabled {
+		return nil
+	}
 	return s.cache.Set(key, data, ttl)
 }
 
 // LoadCache loads data from cache if it exists and hasn't expired
 func (s *Storage) LoadCache(key string, target interface{}) (bool, error) {
+	if s.cacheDisabled {
+		return false, nil
+	}
 	return s.cache.Get(key, target)
 }
 
@@ -15,12 +15,17 @@
 package main
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
 // CalculateConsumptionCosts calculates costs for consumption data using tariff agreements
-// This function uses simple tariff rates (unit rate, day/night rates) from agreements
-func CalculateConsumptionCosts(consumptions []Consumption, agreements []Agreement) []Consumption {
+// This function uses simple tariff rates (unit rate, day/night rates) from agreements.
+// The pricing model decides which rate applies to each slot; when nil it is
+// detected from the tariff active at the time.
+func CalculateConsumptionCosts(consumptions []Consumption, agreements []Agreement, model PricingModel) []Consumption {
 	if len(consumptions) == 0 || len(agreements) == 0 {
 		return consumptions
 	}
@@ -34,7 +39,11 @@ func CalculateConsumptionCosts(consumptions []Consumption, agreements []Agreemen
 		}
 
 		// Determine the rate to use based on time of day
-		rate := getRateForTime(consumptions[i].StartAt, tariff)
+		slotModel := model
+		if slotModel == nil {
+			slotModel = DetectPricingModel(tariff)
+		}
+		rate := tariff.RateForBand(slotModel.BandAt(consumptions[i].StartAt))
 
 		// Calculate cost: consumption (kWh) × rate (p/kWh) = cost in pence
 		consumptions[i].Cost = consumptions[i].Value * rate
@@ -65,6 +74,99 @@ func CalculateConsumptionCostsWithRates(consumptions []Consumption, rates []Tari
 	return consumptions
 }
 
+// CalculateStandingCharges returns the standing charge for each whole day in the range.
+// Products API standing charges are preferred (they vary daily on some products),
+// falling back to the standing charge of the agreement active on that day.
+func CalculateStandingCharges(startDate, endDate time.Time, agreements []Agreement, rates []TariffRate) []DailyStandingCharge {
+	if len(agreements) == 0 && len(rates) == 0 {
+		return nil
+	}
+
+	// Start from the first UK midnight on or after the start of the range
+	day := londonDayStart(startDate)
+	if day.Before(startDate) {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	var charges []DailyStandingCharge
+	for ; day.Before(endDate); day = day.AddDate(0, 0, 1) {
+		if rate := findActiveRate(day, rates); rate != nil {
+			charges = append(charges, DailyStandingCharge{Date: day, Charge: rate.ValueIncVAT})
+			continue
+		}
+		if tariff := findActiveTariff(day, agreements); tariff != nil {
+			charges = append(charges, DailyStandingCharge{Date: day, Charge: tariff.StandingCharge})
+		}
+	}
+
+	return charges
+}
+
+// ApplyDispatchPricing reprices consumption slots overlapping a completed
+// smart-charge dispatch at the off-peak rate, since Intelligent Octopus bills
+// those slots at off-peak even outside the normal window. It returns the kWh
+// repriced and the saving in pence.
+func ApplyDispatchPricing(consumptions []Consumption, dispatches []Dispatch, offPeakRate func(t time.Time) float64) (float64, float64) {
+	if len(consumptions) == 0 || len(dispatches) == 0 {
+		return 0, 0
+	}
+
+	var kwh, saving float64
+	for i := range consumptions {
+		slot := &consumptions[i]
+		if !coveredByDispatch(slot.StartAt, slot.EndAt, dispatches) {
+			continue
+		}
+
+		rate := offPeakRate(slot.StartAt)
+		if rate <= 0 {
+			continue
+		}
+
+		// Slots already in the off-peak window are priced correctly
+		repriced := slot.Value * rate
+		if repriced >= slot.Cost {
+			continue
+		}
+
+		kwh += slot.Value
+		saving += slot.Cost - repriced
+		slot.Cost = repriced
+	}
+
+	return kwh, saving
+}
+
+// coveredByDispatch reports whether any dispatch overlaps the slot
+func coveredByDispatch(start, end time.Time, dispatches []Dispatch) bool {
+	for _, dispatch := range dispatches {
+		if dispatch.Start.Before(end) && dispatch.End.After(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// lowestRateAround returns the cheapest unit rate in effect within 12 hours of t,
+// which for Intelligent Octopus is the overnight off-peak rate
+func lowestRateAround(t time.Time, rates []TariffRate) float64 {
+	from, to := t.Add(-12*time.Hour), t.Add(12*time.Hour)
+
+	lowest := 0.0
+	for _, rate := range rates {
+		if !rate.ValidFrom.Before(to) {
+			continue
+		}
+		if rate.ValidTo != nil && !rate.ValidTo.After(from) {
+			continue
+		}
+		if lowest == 0 || rate.ValueIncVAT < lowest {
+			lowest = rate.ValueIncVAT
+		}
+	}
+	return lowest
+}
+
 // findActiveRate finds the tariff rate that was active at the given time
 func findActiveRate(t time.Time, rates []TariffRate) *TariffRate {
 	for i := range rates {
@@ -95,37 +197,313 @@ func findActiveTariff(t time.Time, agreements []Agreement) *Tariff {
 	return nil
 }
 
-// getRateForTime determines the appropriate rate based on time of day
-func getRateForTime(t time.Time, tariff *Tariff) float64 {
-	// If it's a simple tariff with only unitRate, use that
-	if tariff.UnitRate > 0 && tariff.DayRate == 0 && tariff.NightRate == 0 {
-		return tariff.UnitRate
+// agreementSpan is the part of a period an agreement's tariff was in force
+type agreementSpan struct {
+	Tariff Tariff
+	From   time.Time
+	To     time.Time
+}
+
+// agreementSpans splits a period between the agreements in force during it,
+// oldest first. When none overlap the period, the latest agreement covers all of it.
+func agreementSpans(agreements []Agreement, startDate, endDate time.Time) []agreementSpan {
+	var spans []agreementSpan
+	for _, agreement := range agreements {
+		from, to := agreement.ValidFrom, endDate
+		if agreement.ValidTo != nil && agreement.ValidTo.Before(to) {
+			to = *agreement.ValidTo
+		}
+		if from.Before(startDate) {
+			from = startDate
+		}
+		if from.Before(to) {
+			spans = append(spans, agreementSpan{Tariff: agreement.Tariff, From: from, To: to})
+		}
+	}
+	if len(spans) == 0 && len(agreements) > 0 {
+		spans = append(spans, agreementSpan{Tariff: agreements[len(agreements)-1].Tariff, From: startDate, To: endDate})
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].From.Before(spans[j].From)
+	})
+	return spans
+}
+
+// clipRates limits rates to the time between from and to
+func clipRates(rates []TariffRate, from, to time.Time) []TariffRate {
+	var clipped []TariffRate
+	for _, rate := range rates {
+		if !rate.ValidFrom.Before(to) || (rate.ValidTo != nil && !rate.ValidTo.After(from)) {
+			continue
+		}
+		if rate.ValidFrom.Before(from) {
+			rate.ValidFrom = from
+		}
+		if rate.ValidTo == nil || rate.ValidTo.After(to) {
+			end := to
+			rate.ValidTo = &end
+		}
+		clipped = append(clipped, rate)
+	}
+	return clipped
+}
+
+// currentTariff returns the tariff active now, or the most recent agreement's tariff
+func currentTariff(agreements []Agreement) *Tariff {
+	if tariff := findActiveTariff(now(), agreements); tariff != nil {
+		return tariff
+	}
+	if len(agreements) == 0 {
+		return nil
+	}
+	return &agreements[len(agreements)-1].Tariff
+}
+
+// RateBand identifies which part of a time-of-use tariff a slot is charged at
+type RateBand string
+
+const (
+	BandStandard RateBand = "standard"
+	BandOffPeak  RateBand = "off_peak"
+	BandPeak     RateBand = "peak"
+)
+
+// Pricing model names, accepted by the pricing_model config option
+const (
+	PricingModelFlat          = "flat"
+	PricingModelEconomy7      = "economy7"
+	PricingModelGo            = "go"
+	PricingModelIntelligentGo = "intelligent_go"
+	PricingModelCosy          = "cosy"
+	PricingModelFlux          = "flux"
+	PricingModelAgile         = "agile"
+	PricingModelCustom        = "custom"
+)
+
+// PricingModel decides which rate band applies to a half-hourly slot
+type PricingModel interface {
+	// Name returns the pricing model identifier
+	Name() string
+
+	// BandAt returns the rate band for the slot starting at t
+	BandAt(t time.Time) RateBand
+
+	// Describe returns a human-readable summary of the model's windows
+	Describe() string
+}
+
+// TimeWindow is a daily time range charged at a particular band.
+// Start and End are minutes after midnight; windows may wrap past midnight.
+type TimeWindow struct {
+	Start int
+	End   int
+	Band  RateBand
+}
+
+// contains reports whether the minute of the day falls inside the window
+func (w TimeWindow) contains(minute int) bool {
+	if w.Start <= w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// String formats the window as HH:MM-HH:MM
+func (w TimeWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// WindowPricingModel charges slots by fixed daily windows. Windows are defined
+// either in UTC (GMT all year, as Economy 7 meters are) or in UK local time
+// (moving with BST, as smart tariffs are). Slots outside any window are standard.
+type WindowPricingModel struct {
+	name        string
+	displayName string
+	utc         bool
+	windows     []TimeWindow
+}
+
+// NewWindowPricingModel creates a window-based pricing model
+func NewWindowPricingModel(name, displayName string, utc bool, windows []TimeWindow) *WindowPricingModel {
+	return &WindowPricingModel{
+		name:        name,
+		displayName: displayName,
+		utc:         utc,
+		windows:     windows,
+	}
+}
+
+// Name returns the pricing model identifier
+func (m *WindowPricingModel) Name() string {
+	return m.name
+}
+
+// BandAt returns the rate band for the slot starting at t
+func (m *WindowPricingModel) BandAt(t time.Time) RateBand {
+	if m.utc {
+		t = t.UTC()
+	} else {
+		t = t.In(london)
 	}
 
-	// If it's a day/night tariff, determine which rate to use
-	if tariff.DayRate > 0 || tariff.NightRate > 0 {
-		if isNightRate(t) {
-			return tariff.NightRate
+	minute := t.Hour()*60 + t.Minute()
+	for _, window := range m.windows {
+		if window.contains(minute) {
+			return window.Band
 		}
-		return tariff.DayRate
+	}
+	return BandStandard
+}
+
+// Describe returns a human-readable summary of the model's windows
+func (m *WindowPricingModel) Describe() string {
+	if len(m.windows) == 0 {
+		return m.displayName
+	}
+
+	zone := "UK time"
+	if m.utc {
+		zone = "GMT"
+	}
+
+	var parts []string
+	for _, window := range m.windows {
+		parts = append(parts, fmt.Sprintf("%s %s", strings.ReplaceAll(string(window.Band), "_", "-"), window))
+	}
+	return fmt.Sprintf("%s (%s %s)", m.displayName, strings.Join(parts, ", "), zone)
+}
+
+// Built-in pricing models for common Octopus tariffs
+var (
+	FlatPricing = NewWindowPricingModel(PricingModelFlat, "Flat rate", false, nil)
+
+	// Economy 7 meters switch on GMT and don't change for BST
+	Economy7Pricing = NewWindowPricingModel(PricingModelEconomy7, "Economy 7", true, []TimeWindow{
+		{Start: 30, End: 7*60 + 30, Band: BandOffPeak},
+	})
+
+	GoPricing = NewWindowPricingModel(PricingModelGo, "Octopus Go", false, []TimeWindow{
+		{Start: 30, End: 5*60 + 30, Band: BandOffPeak},
+	})
+
+	IntelligentGoPricing = NewWindowPricingModel(PricingModelIntelligentGo, "Intelligent Octopus Go", false, []TimeWindow{
+		{Start: 23*60 + 30, End: 5*60 + 30, Band: BandOffPeak},
+	})
+
+	CosyPricing = NewWindowPricingModel(PricingModelCosy, "Cosy Octopus", false, []TimeWindow{
+		{Start: 4 * 60, End: 7 * 60, Band: BandOffPeak},
+		{Start: 13 * 60, End: 16 * 60, Band: BandOffPeak},
+		{Start: 16 * 60, End: 19 * 60, Band: BandPeak},
+		{Start: 22 * 60, End: 24 * 60, Band: BandOffPeak},
+	})
+
+	FluxPricing = NewWindowPricingModel(PricingModelFlux, "Octopus Flux", false, []TimeWindow{
+		{Start: 2 * 60, End: 5 * 60, Band: BandOffPeak},
+		{Start: 16 * 60, End: 19 * 60, Band: BandPeak},
+	})
+
+	// Agile prices every slot individually via the Products API; the window
+	// only marks the evening peak for reporting
+	AgilePricing = NewWindowPricingModel(PricingModelAgile, "Agile Octopus", false, []TimeWindow{
+		{Start: 16 * 60, End: 19 * 60, Band: BandPeak},
+	})
+)
+
+// builtinPricingModels maps config names to built-in pricing models
+var builtinPricingModels = map[string]PricingModel{
+	PricingModelFlat:          FlatPricing,
+	PricingModelEconomy7:      Economy7Pricing,
+	PricingModelGo:            GoPricing,
+	PricingModelIntelligentGo: IntelligentGoPricing,
+	PricingModelCosy:          CosyPricing,
+	PricingModelFlux:          FluxPricing,
+	PricingModelAgile:         AgilePricing,
+}
+
+// LookupPricingModel returns the built-in pricing model with the given name
+func LookupPricingModel(name string) (PricingModel, bool) {
+	model, ok := builtinPricingModels[name]
+	return model, ok
+}
+
+// DetectPricingModel picks a pricing model from the tariff's name and rates
+func DetectPricingModel(tariff *Tariff) PricingModel {
+	if tariff == nil {
+		return FlatPricing
+	}
+
+	name := tariff.DisplayName
+	switch {
+	case containsIgnoreCase(name, "agile"):
+		return AgilePricing
+	case containsIgnoreCase(name, "intelligent octopus go"), containsIgnoreCase(name, "intelligent go"):
+		return IntelligentGoPricing
+	case containsIgnoreCase(name, "octopus go"):
+		return GoPricing
+	case containsIgnoreCase(name, "cosy"):
+		return CosyPricing
+	case containsIgnoreCase(name, "flux"):
+		return FluxPricing
+	case containsIgnoreCase(name, "economy 7"), tariff.DayRate > 0 || tariff.NightRate > 0:
+		return Economy7Pricing
 	}
 
-	// Fallback to unit rate
-	return tariff.UnitRate
+	return FlatPricing
 }
 
-// isNightRate determines if the given time falls within night rate hours
-// For Intelligent Octopus Flux, typical night rates are:
-// - 02:00-05:00 (off-peak/cheap rate)
-// This can be made configurable in the future
-func isNightRate(t time.Time) bool {
-	hour := t.Hour()
+// ParseTimeWindow parses HH:MM start and end times into a window.
+// An end of 24:00 or 00:00 means midnight at the end of the day.
+func ParseTimeWindow(start, end string, band RateBand) (TimeWindow, error) {
+	startMinute, err := parseClockTime(start)
+	if err != nil {
+		return TimeWindow{}, err
+	}
+	endMinute, err := parseClockTime(end)
+	if err != nil {
+		return TimeWindow{}, err
+	}
+	if endMinute == 0 {
+		endMinute = 24 * 60
+	}
+	if startMinute == endMinute {
+		return TimeWindow{}, fmt.Errorf("window %s-%s is empty", start, end)
+	}
 
-	// Intelligent Octopus Flux off-peak: 02:00-05:00
-	// Note: This is a common pattern but may vary by tariff
-	if hour >= 2 && hour < 5 {
-		return true
+	switch band {
+	case BandOffPeak, BandPeak, BandStandard:
+	default:
+		return TimeWindow{}, fmt.Errorf("unknown band %q (expected %q, %q or %q)", band, BandOffPeak, BandPeak, BandStandard)
 	}
 
-	return false
+	return TimeWindow{Start: startMinute, End: endMinute, Band: band}, nil
+}
+
+// parseClockTime parses an HH:MM time of day into minutes after midnight
+func parseClockTime(value string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(strings.TrimSpace(value), "%d:%d", &hour, &minute); err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	if hour < 0 || hour > 24 || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// RateForBand returns the agreement rate (p/kWh) charged for a band. Agreements
+// only carry day and night rates, so peak slots are charged at the day rate.
+func (t *Tariff) RateForBand(band RateBand) float64 {
+	// If it's a simple tariff with only unitRate, use that
+	if t.DayRate == 0 && t.NightRate == 0 {
+		if band == BandOffPeak && t.OffPeakRate > 0 {
+			return t.OffPeakRate
+		}
+		return t.UnitRate
+	}
+
+	if band == BandOffPeak {
+		return t.NightRate
+	}
+	return t.DayRate
 }
@@ -0,0 +1,306 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// FixtureModeRecord saves every HTTP exchange to the fixture directory
+	FixtureModeRecord = "record"
+
+	// FixtureModeReplay serves HTTP responses from the fixture directory without network access
+	FixtureModeReplay = "replay"
+
+	// fixtureSessionFile holds metadata about a recorded session
+	fixtureSessionFile = "session.json"
+
+	// redactedValue replaces secrets in recorded requests and tokens in responses
+	redactedValue = "REDACTED"
+)
+
+// Fixture is a single recorded HTTP request and response
+type Fixture struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body,omitempty"`
+	StatusCode  int         `json:"status_code"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body"`
+}
+
+// FixtureSession records when a session was captured
+type FixtureSession struct {
+	RecordedAt time.Time `json:"recorded_at"`
+	Version    string    `json:"version"`
+}
+
+// FixtureTransport is an http.RoundTripper that records HTTP exchanges to disk
+// or replays previously recorded exchanges without touching the network
+type FixtureTransport struct {
+	mode    string
+	dir     string
+	secrets []string
+	next    http.RoundTripper
+	logger  *Logger
+
+	// Identical requests are numbered so repeated calls replay in order
+	counts map[string]int
+	mutex  sync.Mutex
+}
+
+// NewFixtureTransport creates a record or replay transport rooted at dir.
+// Secrets are redacted from recorded requests before they are written or matched.
+func NewFixtureTransport(mode, dir string, secrets []string, logger *Logger) (*FixtureTransport, error) {
+	if mode != FixtureModeRecord && mode != FixtureModeReplay {
+		return nil, &ConfigError{
+			Field:   "fixture_mode",
+			Message: fmt.Sprintf("unknown mode %q (expected %q or %q)", mode, FixtureModeRecord, FixtureModeReplay),
+		}
+	}
+
+	if mode == FixtureModeRecord {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, &StorageError{
+				Operation: "create_fixture_directory",
+				Path:      dir,
+				Err:       err,
+			}
+		}
+	} else if _, err := os.Stat(dir); err != nil {
+		return nil, &StorageError{
+			Operation: "open_fixture_directory",
+			Path:      dir,
+			Err:       err,
+		}
+	}
+
+	var nonEmpty []string
+	for _, secret := range secrets {
+		if secret != "" {
+			nonEmpty = append(nonEmpty, secret)
+		}
+	}
+
+	logger.Info("HTTP fixtures enabled", "mode", mode, "path", dir)
+
+	return &FixtureTransport{
+		mode:    mode,
+		dir:     dir,
+		secrets: nonEmpty,
+		next:    http.DefaultTransport,
+		logger:  logger,
+		counts:  make(map[string]int),
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	url := t.redact(req.URL.String())
+	body := t.redact(string(reqBody))
+	key := fixtureKey(req.Method, url, body)
+
+	t.mutex.Lock()
+	t.counts[key]++
+	seq := t.counts[key]
+	t.mutex.Unlock()
+
+	if t.mode == FixtureModeReplay {
+		return t.replay(req, key, seq)
+	}
+	return t.record(req, key, seq, url, body)
+}
+
+// record performs the real request and saves the exchange
+func (t *FixtureTransport) record(req *http.Request, key string, seq int, url, reqBody string) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	fixture := Fixture{
+		Method:      req.Method,
+		URL:         url,
+		RequestBody: reqBody,
+		StatusCode:  resp.StatusCode,
+		Header:      resp.Header,
+		Body:        redactToken(reqBody, respBody),
+	}
+
+	path := t.fixturePath(key, seq)
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.logger.Warn("Failed to write HTTP fixture", "path", path, "error", err)
+	} else {
+		t.logger.Debug("Recorded HTTP fixture", "method", req.Method, "url", url, "path", path)
+	}
+
+	return resp, nil
+}
+
+// replay serves a recorded response, falling back to the first recording of the
+// request when it is made more often than during the recorded session
+func (t *FixtureTransport) replay(req *http.Request, key string, seq int) (*http.Response, error) {
+	var fixture Fixture
+	path := t.fixturePath(key, seq)
+	data, err := os.ReadFile(path)
+	if err != nil && seq > 1 {
+		path = t.fixturePath(key, 1)
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		t.logger.Warn("No recorded response for request", "method", req.Method, "url", t.redact(req.URL.String()))
+		// Behave like a missing resource so callers fail fast instead of retrying
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Status:     fmt.Sprintf("%d %s", http.StatusNotFound, http.StatusText(http.StatusNotFound)),
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+			Body:       io.NopCloser(strings.NewReader("no recorded response for " + req.Method + " " + req.URL.String())),
+			Request:    req,
+		}, nil
+	}
+
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", path, err)
+	}
+
+	t.logger.Debug("Replayed HTTP fixture", "method", req.Method, "url", fixture.URL, "path", path)
+
+	return &http.Response{
+		StatusCode:    fixture.StatusCode,
+		Status:        fmt.Sprintf("%d %s", fixture.StatusCode, http.StatusText(fixture.StatusCode)),
+		Header:        fixture.Header,
+		Body:          io.NopCloser(strings.NewReader(fixture.Body)),
+		ContentLength: int64(len(fixture.Body)),
+		Request:       req,
+	}, nil
+}
+
+// redact replaces configured secrets in s
+func (t *FixtureTransport) redact(s string) string {
+	for _, secret := range t.secrets {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	return s
+}
+
+// redactToken replaces the Kraken JWT in an obtainKrakenToken response, so
+// shared fixtures can't be used to reach the account. Replay only needs a
+// token to be present, not a valid one.
+func redactToken(reqBody string, respBody []byte) string {
+	if !strings.Contains(reqBody, "obtainKrakenToken") {
+		return string(respBody)
+	}
+
+	var tokenResp ObtainTokenResponse
+	if err := json.Unmarshal(respBody, &tokenResp); err != nil || tokenResp.Data.ObtainKrakenToken.Token == "" {
+		return string(respBody)
+	}
+	tokenResp.Data.ObtainKrakenToken.Token = redactedValue
+
+	redacted, err := json.Marshal(tokenResp)
+	if err != nil {
+		return string(respBody)
+	}
+	return string(redacted)
+}
+
+// fixturePath returns the file path for the seq-th occurrence of a request
+func (t *FixtureTransport) fixturePath(key string, seq int) string {
+	return filepath.Join(t.dir, fmt.Sprintf("%s_%03d.json", key, seq))
+}
+
+// fixtureKey derives a stable file name prefix from a redacted request
+func fixtureKey(method, url, body string) string {
+	sum := sha256.Sum256([]byte(method + "\n" + url + "\n" + body))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SaveFixtureSession writes session metadata alongside recorded fixtures
+func SaveFixtureSession(dir string, recordedAt time.Time) error {
+	session := FixtureSession{
+		RecordedAt: recordedAt,
+		Version:    GetVersion(),
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture session: %w", err)
+	}
+
+	path := filepath.Join(dir, fixtureSessionFile)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return &StorageError{
+			Operation: "write_fixture_session",
+			Path:      path,
+			Err:       err,
+		}
+	}
+
+	return nil
+}
+
+// LoadFixtureSession reads session metadata from a fixture directory
+func LoadFixtureSession(dir string) (*FixtureSession, error) {
+	path := filepath.Join(dir, fixtureSessionFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &StorageError{
+			Operation: "read_fixture_session",
+			Path:      path,
+			Err:       err,
+		}
+	}
+
+	var session FixtureSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture session: %w", err)
+	}
+
+	return &session, nil
+}
@@ -72,7 +72,7 @@ type GitHubRelease struct {
 }
 
 // CheckForUpdates checks if a newer version is available on GitHub
-func CheckForUpdates(logger *Logger) {
+func CheckForUpdates(releasesURL string, logger *Logger) {
 	currentVersion := GetVersion()
 
 	// Skip update check for development builds
@@ -85,7 +85,7 @@ func CheckForUpdates(logger *Logger) {
 		Timeout: 10 * time.Second,
 	}
 
-	resp, err := client.Get("https://api.github.com/repos/matthewgall/octobudget/releases/latest")
+	resp, err := client.Get(releasesURL)
 	if err != nil {
 		logger.Debug("Failed to check for updates", "error", err)
 		return
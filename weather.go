@@ -15,6 +15,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -23,7 +24,9 @@ import (
 
 // WeatherClient fetches historical weather data
 type WeatherClient struct {
+	archiveURL string
 	httpClient *http.Client
+	retry      *RetryPolicy
 	logger     *Logger
 	// UK approximate center coordinates (used if no location specified)
 	latitude  float64
@@ -32,9 +35,12 @@ type WeatherClient struct {
 
 // NewWeatherClient creates a new weather client
 // Default coordinates are for central UK (around Birmingham)
-func NewWeatherClient(logger *Logger) *WeatherClient {
+// A nil transport uses the default HTTP transport
+func NewWeatherClient(config *Config, transport http.RoundTripper, logger *Logger) *WeatherClient {
 	return &WeatherClient{
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		archiveURL: config.WeatherAPIURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second, Transport: transport},
+		retry:      NewRetryPolicy(config.RetryAttempts, logger),
 		logger:     logger,
 		latitude:   52.4862,  // Birmingham, UK
 		longitude:  -1.8904,
@@ -42,7 +48,7 @@ func NewWeatherClient(logger *Logger) *WeatherClient {
 }
 
 // FetchWeatherForDates fetches historical weather data for specific dates
-func (w *WeatherClient) FetchWeatherForDates(dates []time.Time) (map[string]*WeatherData, error) {
+func (w *WeatherClient) FetchWeatherForDates(ctx context.Context, dates []time.Time) (map[string]*WeatherData, error) {
 	if len(dates) == 0 {
 		return nil, nil
 	}
@@ -64,50 +70,70 @@ func (w *WeatherClient) FetchWeatherForDates(dates []time.Time) (map[string]*Wea
 	}
 
 	// Fetch weather data for the date range
-	url := fmt.Sprintf("https://archive-api.open-meteo.com/v1/archive?latitude=%.4f&longitude=%.4f&start_date=%s&end_date=%s&daily=temperature_2m_max,temperature_2m_min,temperature_2m_mean,precipitation_sum,weather_code&timezone=Europe%%2FLondon",
+	url := fmt.Sprintf("%s?latitude=%.4f&longitude=%.4f&start_date=%s&end_date=%s&daily=temperature_2m_max,temperature_2m_min,temperature_2m_mean,precipitation_sum,weather_code&timezone=Europe%%2FLondon",
+		w.archiveURL,
 		w.latitude,
 		w.longitude,
-		startDate.Format("2006-01-02"),
-		endDate.Format("2006-01-02"),
+		londonDate(startDate),
+		londonDate(endDate),
 	)
 
-	w.logger.Info("Fetching weather data", "start", startDate.Format("2006-01-02"), "end", endDate.Format("2006-01-02"))
+	w.logger.Info("Fetching weather data", "start", londonDate(startDate), "end", londonDate(endDate))
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create weather request: %w", err)
-	}
+	var weatherResp OpenMeteoResponse
+	err := w.retry.Do(ctx, url, func() error {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create weather request: %w", err)
+		}
+
+		req.Header.Set("User-Agent", GetUserAgent())
 
-	req.Header.Set("User-Agent", GetUserAgent())
+		resp, err := w.httpClient.Do(req)
+		if err != nil {
+			return &APIError{
+				Endpoint: url,
+				Message:  "failed to fetch weather data",
+				Err:      err,
+			}
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return &APIError{
+				StatusCode: resp.StatusCode,
+				Endpoint:   url,
+				Message:    "weather API returned non-200 status",
+				RetryAfter: parseRetryAfter(resp.Header),
+			}
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&weatherResp); err != nil {
+			return fmt.Errorf("failed to decode weather response: %w", err)
+		}
 
-	resp, err := w.httpClient.Do(req)
+		return nil
+	})
 	if err != nil {
 		w.logger.Warn("Failed to fetch weather data", "error", err)
 		return nil, nil // Non-fatal, return nil to continue without weather
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		w.logger.Warn("Weather API returned non-200 status", "status", resp.StatusCode)
-		return nil, nil // Non-fatal
-	}
-
-	var weatherResp OpenMeteoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&weatherResp); err != nil {
-		w.logger.Warn("Failed to decode weather response", "error", err)
-		return nil, nil // Non-fatal
-	}
 
 	// Convert to map for easy lookup
 	weatherMap := make(map[string]*WeatherData)
 	for i, dateStr := range weatherResp.Daily.Time {
-		date, _ := time.Parse("2006-01-02", dateStr)
+		// The archive lags a few days behind, leaving the latest days empty
+		if i >= len(weatherResp.Daily.TempMean) || weatherResp.Daily.TempMean[i] == nil {
+			continue
+		}
+
+		date, _ := time.ParseInLocation("2006-01-02", dateStr, london)
 		weatherMap[dateStr] = &WeatherData{
 			Date:          date,
-			TempMax:       weatherResp.Daily.TempMax[i],
-			TempMin:       weatherResp.Daily.TempMin[i],
-			TempMean:      weatherResp.Daily.TempMean[i],
-			Precipitation: weatherResp.Daily.Precipitation[i],
+			TempMax:       valueAt(weatherResp.Daily.TempMax, i),
+			TempMin:       valueAt(weatherResp.Daily.TempMin, i),
+			TempMean:      *weatherResp.Daily.TempMean[i],
+			Precipitation: valueAt(weatherResp.Daily.Precipitation, i),
 			WeatherCode:   weatherResp.Daily.WeatherCode[i],
 			WeatherDesc:   getWeatherDescription(weatherResp.Daily.WeatherCode[i]),
 		}
@@ -117,6 +143,14 @@ func (w *WeatherClient) FetchWeatherForDates(dates []time.Time) (map[string]*Wea
 	return weatherMap, nil
 }
 
+// valueAt returns a daily value from the archive, or zero when it's missing
+func valueAt(values []*float64, i int) float64 {
+	if i >= len(values) || values[i] == nil {
+		return 0
+	}
+	return *values[i]
+}
+
 // getWeatherDescription converts WMO weather code to human-readable description
 func getWeatherDescription(code int) string {
 	switch code {
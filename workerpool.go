@@ -0,0 +1,67 @@
+// Copyright 2025 Matthew Gall <me@matthewgall.dev>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// DefaultCollectionWorkers is the default number of API fetches run at once
+	DefaultCollectionWorkers = 4
+
+	// MaxCollectionWorkers caps concurrency to stay well inside the API rate limits
+	MaxCollectionWorkers = 16
+)
+
+// task is a unit of work run by runTasks. Tasks record their own results and
+// errors in variables owned by the caller, which merges them in a fixed order
+// once every task has finished.
+type task func(ctx context.Context)
+
+// runTasks runs tasks on at most workers goroutines and waits for them all to
+// finish. Tasks not yet started when ctx is cancelled are skipped, and the
+// context's error is returned so callers don't merge partial results.
+func runTasks(ctx context.Context, workers int, tasks []task) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+schedule:
+	for _, fn := range tasks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break schedule
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+			fn(ctx)
+		}()
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}